@@ -0,0 +1,80 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster
+
+// ClusterOSIndexPolicy is the Schema for the clusterosindexpolicies API, a cluster-scoped OSIndexPolicy.
+// The Secrets it references are read from the namespace configured in the manager.
+type ClusterOSIndexPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   OSIndexPolicySpec   `json:"spec,omitempty"`
+	Status OSIndexPolicyStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ClusterOSIndexPolicyList contains a list of ClusterOSIndexPolicy.
+type ClusterOSIndexPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClusterOSIndexPolicy `json:"items"`
+}
+
+// IndexPolicyObject is implemented by the kinds sharing the OSIndexPolicy spec and status.
+// +kubebuilder:object:generate=false
+type IndexPolicyObject interface {
+	client.Object
+	// GetSpec returns the spec of the object.
+	GetSpec() *OSIndexPolicySpec
+	// GetStatus returns the status of the object.
+	GetStatus() *OSIndexPolicyStatus
+}
+
+// GetSpec returns the spec of the policy.
+func (p *ClusterOSIndexPolicy) GetSpec() *OSIndexPolicySpec {
+	return &p.Spec
+}
+
+// GetStatus returns the status of the policy.
+func (p *ClusterOSIndexPolicy) GetStatus() *OSIndexPolicyStatus {
+	return &p.Status
+}
+
+// GetSpec returns the spec of the policy.
+func (p *OSIndexPolicy) GetSpec() *OSIndexPolicySpec {
+	return &p.Spec
+}
+
+// GetStatus returns the status of the policy.
+func (p *OSIndexPolicy) GetStatus() *OSIndexPolicyStatus {
+	return &p.Status
+}
+
+func init() {
+	SchemeBuilder.Register(&ClusterOSIndexPolicy{}, &ClusterOSIndexPolicyList{})
+}
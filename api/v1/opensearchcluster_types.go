@@ -0,0 +1,52 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// OpenSearchClusterSpec describes how to reach an OpenSearch cluster.
+type OpenSearchClusterSpec struct {
+	// OpensearhConnection to the cluster. Secrets are read from the namespace of the
+	// OpenSearchCluster, which is the namespace of the policies targeting it.
+	OpensearhConnection OpensearhConnection `json:"opensearch_connection"`
+}
+
+// +kubebuilder:object:root=true
+
+// OpenSearchCluster is the Schema for the opensearchclusters API.
+// OSIndexPolicies list it in spec.targets to sync one policy to several clusters.
+type OpenSearchCluster struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec OpenSearchClusterSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// OpenSearchClusterList contains a list of OpenSearchCluster.
+type OpenSearchClusterList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OpenSearchCluster `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&OpenSearchCluster{}, &OpenSearchClusterList{})
+}
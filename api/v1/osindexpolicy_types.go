@@ -17,12 +17,19 @@ limitations under the License.
 package v1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
 // NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
 
+// +kubebuilder:validation:XValidation:rule="!has(self.template_ref) || !has(self.policy) || !(has(self.policy.description) || has(self.policy.error_notification) || has(self.policy.default_state) || has(self.policy.states) || has(self.policy.ism_template))",message="policy and template_ref are mutually exclusive"
+// +kubebuilder:validation:XValidation:rule="!has(self.template_ref) || !has(self.base)",message="base and template_ref are mutually exclusive"
+// +kubebuilder:validation:XValidation:rule="!has(self.policy_from) || !has(self.policy) || !(has(self.policy.description) || has(self.policy.error_notification) || has(self.policy.default_state) || has(self.policy.states) || has(self.policy.ism_template))",message="policy and policy_from are mutually exclusive"
+// +kubebuilder:validation:XValidation:rule="!has(self.policy_from) || !has(self.template_ref)",message="policy_from and template_ref are mutually exclusive"
+// +kubebuilder:validation:XValidation:rule="!has(self.targets) || !has(self.opensearch_connection) || !(has(self.opensearch_connection.url) || has(self.opensearch_connection.urls) || has(self.opensearch_connection.sniffing) || has(self.opensearch_connection.username) || has(self.opensearch_connection.password) || has(self.opensearch_connection.credentials_secret_ref) || has(self.opensearch_connection.tls) || has(self.opensearch_connection.auth))",message="targets and opensearch_connection are mutually exclusive"
+
 // OSIndexPolicySpec defines the desired state of OSIndexPolicy.
 type OSIndexPolicySpec struct {
 	// INSERT ADDITIONAL SPEC FIELDS - desired state of cluster
@@ -32,17 +39,289 @@ type OSIndexPolicySpec struct {
 	PolicyID string `json:"policy_id,omitempty"`
 	// Target Opensearch
 	OpensearhConnection OpensearhConnection `json:"opensearch_connection,omitempty"`
-	// IndexPolicy defines the ISM policy for the index
+	// IndexPolicy defines the ISM policy for the index. With base, it is an overlay merged
+	// into the policy of the base: states by name, their actions by type and their
+	// transitions by target state. The ism_template of the base is not inherited, other
+	// fields replace those of the base when set.
 	Policy OpensearchIndexPolicy `json:"policy,omitempty"`
+	// AutoRetry retries the managed indices whose ISM action failed. Disabled when unset.
+	// +optional
+	AutoRetry *AutoRetry `json:"auto_retry,omitempty"`
+	// Rollout controls how policy updates reach the indices already managed by the policy
+	// +optional
+	Rollout *Rollout `json:"rollout,omitempty"`
+	// AttachExisting attaches the policy to existing indices the ISM template does not apply to
+	// +optional
+	AttachExisting *AttachExisting `json:"attach_existing,omitempty"`
+	// AdoptionPolicy decides what happens when policy_id already exists in Opensearch and is not
+	// owned by this object: Fail leaves it untouched, Adopt takes over policies without an owner,
+	// Overwrite also takes over policies owned by another OSIndexPolicy.
+	// The owner is recorded at the end of the policy description.
+	// +kubebuilder:validation:Enum=Fail;Adopt;Overwrite
+	// +kubebuilder:default=Fail
+	// +optional
+	AdoptionPolicy string `json:"adoption_policy,omitempty"`
+	// DeletionPolicy decides what happens to the ISM policy when the object is deleted: Delete
+	// removes it from Opensearch, Retain leaves it there. Set Retain to let go of an object whose
+	// cluster can no longer be reached.
+	// +kubebuilder:validation:Enum=Delete;Retain
+	// +kubebuilder:default=Delete
+	// +optional
+	DeletionPolicy string `json:"deletion_policy,omitempty"`
+	// Plan computes the changes the controller would make to Opensearch and reports them in
+	// status and events, without making them. The manager --dry-run flag plans every object.
+	// +optional
+	Plan bool `json:"plan,omitempty"`
+	// ResyncInterval overrides the manager --resync-interval, the time between two checks of
+	// the policy for drift in Opensearch. Spec changes are applied right away.
+	// +optional
+	ResyncInterval *metav1.Duration `json:"resync_interval,omitempty"`
+	// TemplateRef renders the policy from an OSIndexPolicyTemplate of the namespace instead of spec.policy
+	// +optional
+	TemplateRef *PolicyTemplateRef `json:"template_ref,omitempty"`
+	// Base inherits the resolved policy of another object of the same kind and namespace,
+	// with spec.policy applied over it
+	// +optional
+	Base *PolicyBaseRef `json:"base,omitempty"`
+	// PolicyFrom reads the policy from native ISM JSON instead of spec.policy. With base,
+	// it is the overlay merged into the policy of the base.
+	// +optional
+	PolicyFrom *PolicySource `json:"policy_from,omitempty"`
+	// Targets syncs the policy to every selected OpenSearchCluster instead of
+	// opensearch_connection, reporting each in status.targets
+	// +optional
+	Targets *PolicyTargets `json:"targets,omitempty"`
+}
+
+// +kubebuilder:validation:XValidation:rule="has(self.clusters) || has(self.selector)",message="targets must set clusters or selector"
+
+// PolicyTargets selects OpenSearchClusters of the namespace. ClusterOSIndexPolicies
+// select them in the namespace of the manager. A cluster selected both ways is synced once.
+type PolicyTargets struct {
+	// Clusters are names of OpenSearchClusters
+	// +optional
+	Clusters []string `json:"clusters,omitempty"`
+	// Selector selects OpenSearchClusters by label
+	// +optional
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+}
+
+// PolicySource is a source of native ISM JSON, the policy object of the OpenSearch
+// ISM API or the document wrapping it in "policy".
+type PolicySource struct {
+	// ConfigMapKeyRef selects a key of a ConfigMap of the namespace. ClusterOSIndexPolicies
+	// read the ConfigMap from the namespace of the manager.
+	ConfigMapKeyRef corev1.ConfigMapKeySelector `json:"config_map_key_ref"`
+}
+
+// PolicyBaseRef references the object a policy inherits from.
+type PolicyBaseRef struct {
+	// Name of the OSIndexPolicy, or of the ClusterOSIndexPolicy for a ClusterOSIndexPolicy
+	Name string `json:"name"`
+}
+
+// PolicyTemplateRef references an OSIndexPolicyTemplate and sets its parameters.
+type PolicyTemplateRef struct {
+	// Name of the OSIndexPolicyTemplate
+	Name string `json:"name"`
+	// Parameters are the values of the template parameters, by name
+	// +optional
+	Parameters map[string]string `json:"parameters,omitempty"`
+}
+
+// Adoption policies.
+const (
+	// AdoptionFail leaves policies not owned by the object untouched and reports it in status
+	AdoptionFail = "Fail"
+	// AdoptionAdopt takes over policies without an owner
+	AdoptionAdopt = "Adopt"
+	// AdoptionOverwrite takes over policies without an owner or owned by another object
+	AdoptionOverwrite = "Overwrite"
+)
+
+// Deletion policies.
+const (
+	// DeletionPolicyDelete deletes the ISM policy from Opensearch with the object
+	DeletionPolicyDelete = "Delete"
+	// DeletionPolicyRetain leaves the ISM policy in Opensearch when the object is deleted
+	DeletionPolicyRetain = "Retain"
+)
+
+// Attach modes.
+const (
+	// AttachModePreview lists the indices that would be attached in status, without attaching them
+	AttachModePreview = "Preview"
+	// AttachModeAttach attaches the policy to the matching unmanaged indices
+	AttachModeAttach = "Attach"
+)
+
+// AttachExisting selects the existing indices the policy is attached to with the ISM add API.
+// Indices already managed by a policy are never changed.
+type AttachExisting struct {
+	// IndexPatterns are the index patterns of the indices to attach, e.g. logs-*
+	// +kubebuilder:validation:MinItems=1
+	IndexPatterns []string `json:"index_patterns"`
+	// Exclude lists index names or wildcard patterns left unmanaged
+	// +optional
+	Exclude []string `json:"exclude,omitempty"`
+	// Mode is Preview, which only reports the matching indices in status, or Attach
+	// +kubebuilder:validation:Enum=Preview;Attach
+	// +kubebuilder:default=Preview
+	// +optional
+	Mode string `json:"mode,omitempty"`
+}
+
+// Rollout strategies.
+const (
+	// RolloutNewIndicesOnly leaves managed indices on the policy version they were attached with
+	RolloutNewIndicesOnly = "NewIndicesOnly"
+	// RolloutChangePolicyAll switches the managed indices to the updated policy, in their current state
+	RolloutChangePolicyAll = "ChangePolicyAll"
+	// RolloutChangePolicyWithStateMapping switches the managed indices to the updated policy,
+	// moving those in a mapped state to the mapped target state
+	RolloutChangePolicyWithStateMapping = "ChangePolicyWithStateMapping"
+)
+
+// Rollout phases.
+const (
+	// RolloutPhasePending waits for the managed indices to be listed
+	RolloutPhasePending = "Pending"
+	// RolloutPhaseChanging waits for change_policy to accept the pending indices
+	RolloutPhaseChanging = "Changing"
+	// RolloutPhaseRequested waits for ISM to switch the requested indices
+	RolloutPhaseRequested = "Requested"
+)
+
+// Rollout defines how an updated policy is applied to the managed indices with the ISM change_policy API
+// +kubebuilder:validation:XValidation:rule="self.strategy != 'ChangePolicyWithStateMapping' || (has(self.state_mappings) && size(self.state_mappings) > 0)",message="state_mappings must be set for ChangePolicyWithStateMapping"
+type Rollout struct {
+	// Strategy is one of NewIndicesOnly, ChangePolicyAll or ChangePolicyWithStateMapping
+	// +kubebuilder:validation:Enum=NewIndicesOnly;ChangePolicyAll;ChangePolicyWithStateMapping
+	// +kubebuilder:default=NewIndicesOnly
+	// +optional
+	Strategy string `json:"strategy,omitempty"`
+	// Include restricts the change to the indices currently in these states, all states when empty
+	// +optional
+	Include []string `json:"include,omitempty"`
+	// StateMappings moves the indices in a state of the old policy to a state of the updated policy.
+	// Indices in unmapped states are not changed.
+	// +optional
+	StateMappings []StateMapping `json:"state_mappings,omitempty"`
+}
+
+// StateMapping maps a state of the old policy to a state of the updated policy
+type StateMapping struct {
+	// From is the current state of the indices
+	From string `json:"from"`
+	// To is the state of the updated policy the indices move to
+	To string `json:"to"`
+}
+
+// AutoRetry defines how failed managed indices are retried with the ISM retry API
+type AutoRetry struct {
+	// MaxAttempts is the number of retries of an index before giving up
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=3
+	// +optional
+	MaxAttempts int `json:"max_attempts,omitempty"`
+	// Backoff is the wait between the first two retries of an index, doubled on every further attempt.
+	// The first retry is sent as soon as the failure is detected.
+	// +kubebuilder:default="5m"
+	// +optional
+	Backoff *metav1.Duration `json:"backoff,omitempty"`
+	// States restricts retries to indices failed in these states, all states when empty
+	// +optional
+	States []string `json:"states,omitempty"`
 }
 
 type OpensearhConnection struct {
 	// URL of the Opensearch instance
 	URL string `json:"url,omitempty"`
+	// URLs lists further nodes of the same cluster. Requests are spread
+	// round-robin over URL and URLs and fail over to the next node on connection errors.
+	// +optional
+	URLs []string `json:"urls,omitempty"`
+	// Sniffing discovers the cluster nodes from the nodes info API
+	// +optional
+	Sniffing *OpensearchSniffing `json:"sniffing,omitempty"`
 	// Username for authentication
 	Username string `json:"username,omitempty"`
 	// Password for authentication
 	Password string `json:"password,omitempty"`
+	// CredentialsSecretRef references a Secret in the policy namespace holding
+	// the "username" and "password" keys. It takes precedence over Username and Password.
+	CredentialsSecretRef *corev1.LocalObjectReference `json:"credentials_secret_ref,omitempty"`
+	// TLS configures how the Opensearch server certificate is verified
+	TLS *OpensearchTLS `json:"tls,omitempty"`
+	// Auth selects how requests are authenticated. It replaces username, password
+	// and credentials_secret_ref, which are kept as a shorthand for basic auth.
+	Auth *OpensearchAuth `json:"auth,omitempty"`
+}
+
+// OpensearchSniffing defines how the nodes of the cluster are discovered.
+// Do not enable it when the nodes are only reachable through a load balancer or proxy.
+type OpensearchSniffing struct {
+	// OnStart discovers the nodes when the client is created
+	OnStart bool `json:"on_start,omitempty"`
+	// Interval rediscovers the nodes periodically, disabled when unset
+	// +optional
+	Interval *metav1.Duration `json:"interval,omitempty"`
+}
+
+// OpensearchAuth defines how requests to Opensearch are authenticated.
+// Exactly one mode must be set.
+// +kubebuilder:validation:XValidation:rule="[has(self.basic), has(self.bearer_token_secret_ref), has(self.api_key_secret_ref), has(self.client_certificate), has(self.aws)].filter(x, x).size() == 1",message="exactly one authentication mode must be set"
+type OpensearchAuth struct {
+	// Basic authenticates with a username and password
+	Basic *BasicAuth `json:"basic,omitempty"`
+	// BearerTokenSecretRef selects the Secret key holding a token sent as
+	// "Authorization: Bearer", e.g. a JWT for the security plugin
+	BearerTokenSecretRef *corev1.SecretKeySelector `json:"bearer_token_secret_ref,omitempty"`
+	// APIKeySecretRef selects the Secret key holding an API key sent as "Authorization: ApiKey"
+	APIKeySecretRef *corev1.SecretKeySelector `json:"api_key_secret_ref,omitempty"`
+	// ClientCertificate authenticates with a TLS client certificate
+	ClientCertificate *ClientCertificateAuth `json:"client_certificate,omitempty"`
+	// AWS signs requests with AWS Signature Version 4, for Amazon OpenSearch Service
+	AWS *AWSAuth `json:"aws,omitempty"`
+}
+
+// BasicAuth defines HTTP basic authentication
+type BasicAuth struct {
+	// CredentialsSecretRef references a Secret in the policy namespace holding the
+	// "username" and "password" keys
+	CredentialsSecretRef corev1.LocalObjectReference `json:"credentials_secret_ref"`
+}
+
+// ClientCertificateAuth defines TLS client certificate authentication
+type ClientCertificateAuth struct {
+	// SecretRef references a kubernetes.io/tls Secret in the policy namespace
+	// holding the "tls.crt" and "tls.key" keys
+	SecretRef corev1.LocalObjectReference `json:"secret_ref"`
+}
+
+// AWSAuth defines AWS Signature Version 4 authentication
+type AWSAuth struct {
+	// Region of the Amazon OpenSearch Service domain or serverless collection
+	Region string `json:"region"`
+	// Service is "es" for managed domains or "aoss" for OpenSearch Serverless
+	// +kubebuilder:validation:Enum=es;aoss
+	// +kubebuilder:default=es
+	// +optional
+	Service string `json:"service,omitempty"`
+	// CredentialsSecretRef references a Secret in the policy namespace holding the
+	// "aws_access_key_id", "aws_secret_access_key" and optional "aws_session_token" keys.
+	// When unset, the web identity token of the manager service account (IRSA) is used.
+	CredentialsSecretRef *corev1.LocalObjectReference `json:"credentials_secret_ref,omitempty"`
+	// RoleARN is the role assumed with the web identity token, defaults to AWS_ROLE_ARN
+	RoleARN string `json:"role_arn,omitempty"`
+}
+
+// OpensearchTLS defines the TLS settings used to connect to Opensearch
+type OpensearchTLS struct {
+	// InsecureSkipVerify disables verification of the server certificate
+	InsecureSkipVerify bool `json:"insecure_skip_verify,omitempty"`
+	// CASecretRef references a Secret in the policy namespace holding the "ca.crt" key
+	CASecretRef *corev1.LocalObjectReference `json:"ca_secret_ref,omitempty"`
 }
 
 // OpensearchIndexPolicy define the desired state of Opensearch Index ISM policy
@@ -172,10 +451,261 @@ type ForceMerge struct {
 	TaskExecutionTimeout string `json:"task_execution_timeout,omitempty"`
 }
 
+// Condition types reported in OSIndexPolicyStatus.
+const (
+	// ConditionReachable reports whether the target Opensearch cluster answers requests
+	ConditionReachable = "Reachable"
+	// ConditionSynced reports whether the ISM policy in Opensearch matches the spec
+	ConditionSynced = "Synced"
+	// ConditionIndicesHealthy reports whether ISM reports failures on the managed indices
+	ConditionIndicesHealthy = "IndicesHealthy"
+	// ConditionPaused is set while the paused annotation stops the controller from reconciling the policy
+	ConditionPaused = "Paused"
+)
+
+// Annotations controlling the reconciliation of an OSIndexPolicy.
+const (
+	// AnnotationPaused set to "true" stops the controller from reconciling the policy
+	AnnotationPaused = "opensearch.a8uhnf.com/paused"
+	// AnnotationResyncAt set to a new value, e.g. the current timestamp, forces the controller to
+	// write the policy to Opensearch and refresh the status, even when nothing changed
+	AnnotationResyncAt = "opensearch.a8uhnf.com/resync-at"
+)
+
+// ManagedIndicesStatus summarizes the indices managed by the policy, from the ISM explain API
+type ManagedIndicesStatus struct {
+	// Total is the number of indices managed by the policy
+	Total int `json:"total"`
+	// States counts the managed indices per ISM state
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	States []ManagedIndexStateStatus `json:"states,omitempty"`
+	// FailedCount is the number of managed indices whose current action failed
+	FailedCount int `json:"failed_count,omitempty"`
+	// FailedIndices lists the failed indices, truncated to the first 20 by name
+	// +optional
+	FailedIndices []FailedIndex `json:"failed_indices,omitempty"`
+	// LastExplainTime is when the explain API last reported a change. The explain API is
+	// queried at most once per explain interval since that time.
+	LastExplainTime metav1.Time `json:"last_explain_time,omitempty"`
+}
+
+// ManagedIndexStateStatus counts the managed indices in an ISM state
+type ManagedIndexStateStatus struct {
+	// Name of the state, "initializing" for indices ISM has not initialized yet
+	Name string `json:"name"`
+	// Count of the indices in the state
+	Count int `json:"count"`
+	// OldestIndex is the index that entered the state first
+	OldestIndex string `json:"oldest_index,omitempty"`
+	// OldestSince is when OldestIndex entered the state
+	OldestSince *metav1.Time `json:"oldest_since,omitempty"`
+}
+
+// FailedIndex is a managed index whose current ISM action failed
+type FailedIndex struct {
+	// Index name
+	Index string `json:"index"`
+	// State of the index
+	State string `json:"state,omitempty"`
+	// Action that failed
+	Action string `json:"action,omitempty"`
+	// Message reported by ISM in info.message
+	Message string `json:"message,omitempty"`
+}
+
 // OSIndexPolicyStatus defines the observed state of OSIndexPolicy.
 type OSIndexPolicyStatus struct {
 	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
 	// Important: Run "make" to regenerate code after modifying this file
+
+	// Conditions represent the latest available observations of the policy state
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// ClusterDistribution is the detected distribution of the target cluster, "opensearch" or "opendistro"
+	ClusterDistribution string `json:"cluster_distribution,omitempty"`
+	// ClusterVersion is the detected version of the target cluster
+	ClusterVersion string `json:"cluster_version,omitempty"`
+	// ManagedIndices summarizes the state of the indices managed by the policy
+	ManagedIndices *ManagedIndicesStatus `json:"managed_indices,omitempty"`
+	// Rollout reports the progress of the last policy update to the managed indices
+	// +optional
+	Rollout *RolloutStatus `json:"rollout,omitempty"`
+	// RetryAttempts records the automatic retries of the failed managed indices
+	// +listType=map
+	// +listMapKey=index
+	// +optional
+	RetryAttempts []IndexRetryStatus `json:"retry_attempts,omitempty"`
+	// AttachExisting reports the unmanaged indices matching spec.attach_existing
+	// +optional
+	AttachExisting *AttachExistingStatus `json:"attach_existing,omitempty"`
+	// LastResyncAt is the value of the resync-at annotation last acted on
+	// +optional
+	LastResyncAt string `json:"last_resync_at,omitempty"`
+	// Plan reports the operation the controller would make, set in plan mode only
+	// +optional
+	Plan *PlanStatus `json:"plan,omitempty"`
+	// EffectivePolicyID is the ID of the ISM policy in OpenSearch, prefixed with
+	// the namespace when the manager runs with --namespace-prefix
+	// +optional
+	EffectivePolicyID string `json:"effective_policy_id,omitempty"`
+	// EffectiveIndexPatterns are the ism_template index patterns sent to OpenSearch
+	// +optional
+	EffectiveIndexPatterns []string `json:"effective_index_patterns,omitempty"`
+	// PolicyHash is the hash of the policy sent to OpenSearch, after rendering spec.template_ref
+	// +optional
+	PolicyHash string `json:"policy_hash,omitempty"`
+	// ResolvedPolicy is the policy sent to OpenSearch when it is composed from spec.base,
+	// spec.template_ref or spec.policy_from
+	// +optional
+	ResolvedPolicy *OpensearchIndexPolicy `json:"resolved_policy,omitempty"`
+	// SyncedPolicy identifies the ISM policy last written to OpenSearch or found in sync
+	// +optional
+	SyncedPolicy *SyncedPolicy `json:"synced_policy,omitempty"`
+	// Targets reports the policy in each OpenSearchCluster of spec.targets. The Synced and
+	// Reachable conditions of the policy are True when they are True for every target.
+	// +listType=map
+	// +listMapKey=cluster
+	// +optional
+	Targets []TargetStatus `json:"targets,omitempty"`
+}
+
+// TargetStatus is the observed state of the policy in one OpenSearchCluster of spec.targets.
+type TargetStatus struct {
+	// Cluster is the name of the OpenSearchCluster
+	Cluster string `json:"cluster"`
+	// URL of the cluster
+	// +optional
+	URL string `json:"url,omitempty"`
+	// Conditions are the Synced and Reachable conditions of the policy in the cluster
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// ClusterDistribution is the detected distribution of the cluster
+	ClusterDistribution string `json:"cluster_distribution,omitempty"`
+	// ClusterVersion is the detected version of the cluster
+	ClusterVersion string `json:"cluster_version,omitempty"`
+	// ManagedIndices summarizes the state of the indices managed by the policy in the cluster
+	ManagedIndices *ManagedIndicesStatus `json:"managed_indices,omitempty"`
+	// Rollout reports the progress of the last policy update in the cluster
+	// +optional
+	Rollout *RolloutStatus `json:"rollout,omitempty"`
+	// RetryAttempts records the automatic retries of the failed managed indices of the cluster
+	// +listType=map
+	// +listMapKey=index
+	// +optional
+	RetryAttempts []IndexRetryStatus `json:"retry_attempts,omitempty"`
+	// AttachExisting reports the unmanaged indices of the cluster matching spec.attach_existing
+	// +optional
+	AttachExisting *AttachExistingStatus `json:"attach_existing,omitempty"`
+	// Plan reports the operation the controller would make in the cluster, set in plan mode only
+	// +optional
+	Plan *PlanStatus `json:"plan,omitempty"`
+	// SyncedPolicy identifies the ISM policy last written to the cluster or found in sync
+	// +optional
+	SyncedPolicy *SyncedPolicy `json:"synced_policy,omitempty"`
+}
+
+// SyncedPolicy identifies a version of the ISM policy the controller wrote or found in sync
+type SyncedPolicy struct {
+	// PolicyID is the ID of the ISM policy in OpenSearch
+	PolicyID string `json:"policy_id"`
+	// SeqNo is the sequence number of the version, 0 until it was read back after its creation
+	// +optional
+	SeqNo int64 `json:"seq_no,omitempty"`
+	// PrimaryTerm is the primary term of the version, 0 until it was read back after its creation
+	// +optional
+	PrimaryTerm int64 `json:"primary_term,omitempty"`
+	// Hash is the hash of the policy rendered from the spec
+	Hash string `json:"hash"`
+}
+
+// Planned operations.
+const (
+	// PlanCreate creates the ISM policy
+	PlanCreate = "Create"
+	// PlanUpdate updates the ISM policy
+	PlanUpdate = "Update"
+	// PlanDelete deletes the ISM policy
+	PlanDelete = "Delete"
+	// PlanNoOp leaves the ISM policy as it is
+	PlanNoOp = "NoOp"
+)
+
+// PlanStatus reports the operation the controller would make on the ISM policy
+type PlanStatus struct {
+	// Operation is one of Create, Update, Delete or NoOp
+	Operation string `json:"operation"`
+	// Diff lists the fields the operation changes, truncated to the first 50
+	// +optional
+	Diff []string `json:"diff,omitempty"`
+	// PlanTime is when the operation was planned
+	PlanTime metav1.Time `json:"plan_time,omitempty"`
+}
+
+// AttachExistingStatus reports the unmanaged indices matching spec.attach_existing
+type AttachExistingStatus struct {
+	// Mode the indices were last checked in
+	Mode string `json:"mode"`
+	// MatchingCount is the number of unmanaged indices matching the patterns at the last check
+	MatchingCount int `json:"matching_count"`
+	// MatchingIndices lists the matching unmanaged indices, truncated to the first 20 by name.
+	// In Preview mode these are the indices Attach would change.
+	// +optional
+	MatchingIndices []string `json:"matching_indices,omitempty"`
+	// AttachedCount is the number of indices the policy was attached to at the last check
+	AttachedCount int `json:"attached_count,omitempty"`
+	// FailedIndices lists the indices the ISM add API rejected, truncated to the first 20 by name
+	// +optional
+	FailedIndices []FailedIndex `json:"failed_indices,omitempty"`
+	// LastCheckTime is when listing the matching indices last reported a change. They are
+	// listed at most once per explain interval since that time.
+	LastCheckTime metav1.Time `json:"last_check_time,omitempty"`
+}
+
+// RolloutStatus reports the progress of a policy update to the managed indices
+type RolloutStatus struct {
+	// Strategy used for the rollout
+	Strategy string `json:"strategy"`
+	// PolicySeqNo is the sequence number of the policy version rolled out
+	PolicySeqNo int64 `json:"policy_seq_no"`
+	// Phase is Pending, Changing or Requested. A rollout interrupted by a failure
+	// is resumed by the next reconcile until it reaches Requested.
+	// +optional
+	Phase string `json:"phase,omitempty"`
+	// PendingIndices lists the managed indices change_policy was not sent to yet
+	// +optional
+	PendingIndices []string `json:"pending_indices,omitempty"`
+	// StartTime is when change_policy was called
+	StartTime metav1.Time `json:"start_time,omitempty"`
+	// Requested is the number of indices change_policy accepted
+	Requested int `json:"requested"`
+	// Completed is the number of managed indices running the rolled out policy version.
+	// ISM switches an index once its current action is done.
+	Completed int `json:"completed"`
+	// Total is the number of managed indices at the last explain sweep
+	Total int `json:"total"`
+	// FailedIndices lists the indices change_policy rejected, truncated to the first 20 by name
+	// +optional
+	FailedIndices []FailedIndex `json:"failed_indices,omitempty"`
+}
+
+// IndexRetryStatus records the automatic retries of a failed managed index
+type IndexRetryStatus struct {
+	// Index name
+	Index string `json:"index"`
+	// State the index failed in. Attempts start over once the index moves to another state.
+	State string `json:"state,omitempty"`
+	// Attempts is the number of retries sent so far, including those that failed
+	Attempts int `json:"attempts"`
+	// LastAttemptTime is when the last retry was sent
+	LastAttemptTime metav1.Time `json:"last_attempt_time,omitempty"`
+	// LastError is why the last retry could not be sent, if it could not
+	LastError string `json:"last_error,omitempty"`
 }
 
 // +kubebuilder:object:root=true
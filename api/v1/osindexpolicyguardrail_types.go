@@ -0,0 +1,61 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// OSIndexPolicyGuardrailSpec restricts the OSIndexPolicies of a set of namespaces.
+type OSIndexPolicyGuardrailSpec struct {
+	// Namespaces the guardrail applies to, as names or glob patterns such as "team-*".
+	// +kubebuilder:validation:MinItems=1
+	Namespaces []string `json:"namespaces"`
+	// AllowedClusters lists the OpenSearch URLs the namespaces may target, as
+	// URLs or glob patterns such as "https://*.logs.example.com:9200". Empty allows any cluster.
+	// +optional
+	AllowedClusters []string `json:"allowed_clusters,omitempty"`
+	// AllowedIndexPatternPrefixes lists the prefixes the index patterns of the
+	// namespaces must start with, in ism_template and attach_existing. Empty allows any pattern.
+	// +optional
+	AllowedIndexPatternPrefixes []string `json:"allowed_index_pattern_prefixes,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster
+
+// OSIndexPolicyGuardrail is the Schema for the osindexpolicyguardrails API.
+// A namespace matched by several guardrails may use what any of them allows.
+type OSIndexPolicyGuardrail struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec OSIndexPolicyGuardrailSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// OSIndexPolicyGuardrailList contains a list of OSIndexPolicyGuardrail.
+type OSIndexPolicyGuardrailList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OSIndexPolicyGuardrail `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&OSIndexPolicyGuardrail{}, &OSIndexPolicyGuardrailList{})
+}
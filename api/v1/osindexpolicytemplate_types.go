@@ -0,0 +1,68 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// OSIndexPolicyTemplateSpec holds an ISM policy with parameters.
+type OSIndexPolicyTemplateSpec struct {
+	// Parameters declares the parameters the policy references as ${name} in its string values
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Parameters []TemplateParameter `json:"parameters,omitempty"`
+	// Policy is the ISM policy rendered for every OSIndexPolicy referencing the template
+	Policy OpensearchIndexPolicy `json:"policy"`
+}
+
+// TemplateParameter declares a parameter of an OSIndexPolicyTemplate.
+type TemplateParameter struct {
+	// Name of the parameter, referenced as ${name}
+	// +kubebuilder:validation:Pattern=`^[A-Za-z_][A-Za-z0-9_]*$`
+	Name string `json:"name"`
+	// Description of the parameter
+	// +optional
+	Description string `json:"description,omitempty"`
+	// Default is used when the OSIndexPolicy does not set the parameter. Without it, the parameter is required.
+	// +optional
+	Default *string `json:"default,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// OSIndexPolicyTemplate is the Schema for the osindexpolicytemplates API.
+type OSIndexPolicyTemplate struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec OSIndexPolicyTemplateSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// OSIndexPolicyTemplateList contains a list of OSIndexPolicyTemplate.
+type OSIndexPolicyTemplateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OSIndexPolicyTemplate `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&OSIndexPolicyTemplate{}, &OSIndexPolicyTemplateList{})
+}
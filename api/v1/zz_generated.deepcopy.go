@@ -21,9 +21,31 @@ limitations under the License.
 package v1
 
 import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AWSAuth) DeepCopyInto(out *AWSAuth) {
+	*out = *in
+	if in.CredentialsSecretRef != nil {
+		in, out := &in.CredentialsSecretRef, &out.CredentialsSecretRef
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AWSAuth.
+func (in *AWSAuth) DeepCopy() *AWSAuth {
+	if in == nil {
+		return nil
+	}
+	out := new(AWSAuth)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Action) DeepCopyInto(out *Action) {
 	*out = *in
@@ -135,156 +157,149 @@ func (in *AllocationAction) DeepCopy() *AllocationAction {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CloseAction) DeepCopyInto(out *CloseAction) {
+func (in *AttachExisting) DeepCopyInto(out *AttachExisting) {
 	*out = *in
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloseAction.
-func (in *CloseAction) DeepCopy() *CloseAction {
-	if in == nil {
-		return nil
+	if in.IndexPatterns != nil {
+		in, out := &in.IndexPatterns, &out.IndexPatterns
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
-	out := new(CloseAction)
-	in.DeepCopyInto(out)
-	return out
-}
-
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ConvertIndexToRemoteAction) DeepCopyInto(out *ConvertIndexToRemoteAction) {
-	*out = *in
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConvertIndexToRemoteAction.
-func (in *ConvertIndexToRemoteAction) DeepCopy() *ConvertIndexToRemoteAction {
-	if in == nil {
-		return nil
+	if in.Exclude != nil {
+		in, out := &in.Exclude, &out.Exclude
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
-	out := new(ConvertIndexToRemoteAction)
-	in.DeepCopyInto(out)
-	return out
-}
-
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DeleteAction) DeepCopyInto(out *DeleteAction) {
-	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeleteAction.
-func (in *DeleteAction) DeepCopy() *DeleteAction {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AttachExisting.
+func (in *AttachExisting) DeepCopy() *AttachExisting {
 	if in == nil {
 		return nil
 	}
-	out := new(DeleteAction)
+	out := new(AttachExisting)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ForceMerge) DeepCopyInto(out *ForceMerge) {
+func (in *AttachExistingStatus) DeepCopyInto(out *AttachExistingStatus) {
 	*out = *in
+	if in.MatchingIndices != nil {
+		in, out := &in.MatchingIndices, &out.MatchingIndices
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.FailedIndices != nil {
+		in, out := &in.FailedIndices, &out.FailedIndices
+		*out = make([]FailedIndex, len(*in))
+		copy(*out, *in)
+	}
+	in.LastCheckTime.DeepCopyInto(&out.LastCheckTime)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ForceMerge.
-func (in *ForceMerge) DeepCopy() *ForceMerge {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AttachExistingStatus.
+func (in *AttachExistingStatus) DeepCopy() *AttachExistingStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(ForceMerge)
+	out := new(AttachExistingStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ForceMergeAction) DeepCopyInto(out *ForceMergeAction) {
+func (in *AutoRetry) DeepCopyInto(out *AutoRetry) {
 	*out = *in
-	if in.ForceMerge != nil {
-		in, out := &in.ForceMerge, &out.ForceMerge
-		*out = new(ForceMerge)
+	if in.Backoff != nil {
+		in, out := &in.Backoff, &out.Backoff
+		*out = new(metav1.Duration)
 		**out = **in
 	}
+	if in.States != nil {
+		in, out := &in.States, &out.States
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ForceMergeAction.
-func (in *ForceMergeAction) DeepCopy() *ForceMergeAction {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoRetry.
+func (in *AutoRetry) DeepCopy() *AutoRetry {
 	if in == nil {
 		return nil
 	}
-	out := new(ForceMergeAction)
+	out := new(AutoRetry)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ISMTemplate) DeepCopyInto(out *ISMTemplate) {
+func (in *BasicAuth) DeepCopyInto(out *BasicAuth) {
 	*out = *in
-	if in.IndexPatterns != nil {
-		in, out := &in.IndexPatterns, &out.IndexPatterns
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
+	out.CredentialsSecretRef = in.CredentialsSecretRef
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ISMTemplate.
-func (in *ISMTemplate) DeepCopy() *ISMTemplate {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BasicAuth.
+func (in *BasicAuth) DeepCopy() *BasicAuth {
 	if in == nil {
 		return nil
 	}
-	out := new(ISMTemplate)
+	out := new(BasicAuth)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *IndexPriorityAction) DeepCopyInto(out *IndexPriorityAction) {
+func (in *ClientCertificateAuth) DeepCopyInto(out *ClientCertificateAuth) {
 	*out = *in
+	out.SecretRef = in.SecretRef
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IndexPriorityAction.
-func (in *IndexPriorityAction) DeepCopy() *IndexPriorityAction {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClientCertificateAuth.
+func (in *ClientCertificateAuth) DeepCopy() *ClientCertificateAuth {
 	if in == nil {
 		return nil
 	}
-	out := new(IndexPriorityAction)
+	out := new(ClientCertificateAuth)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NotifyAction) DeepCopyInto(out *NotifyAction) {
+func (in *CloseAction) DeepCopyInto(out *CloseAction) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotifyAction.
-func (in *NotifyAction) DeepCopy() *NotifyAction {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloseAction.
+func (in *CloseAction) DeepCopy() *CloseAction {
 	if in == nil {
 		return nil
 	}
-	out := new(NotifyAction)
+	out := new(CloseAction)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OSIndexPolicy) DeepCopyInto(out *OSIndexPolicy) {
+func (in *ClusterOSIndexPolicy) DeepCopyInto(out *ClusterOSIndexPolicy) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OSIndexPolicy.
-func (in *OSIndexPolicy) DeepCopy() *OSIndexPolicy {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterOSIndexPolicy.
+func (in *ClusterOSIndexPolicy) DeepCopy() *ClusterOSIndexPolicy {
 	if in == nil {
 		return nil
 	}
-	out := new(OSIndexPolicy)
+	out := new(ClusterOSIndexPolicy)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *OSIndexPolicy) DeepCopyObject() runtime.Object {
+func (in *ClusterOSIndexPolicy) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -292,31 +307,31 @@ func (in *OSIndexPolicy) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OSIndexPolicyList) DeepCopyInto(out *OSIndexPolicyList) {
+func (in *ClusterOSIndexPolicyList) DeepCopyInto(out *ClusterOSIndexPolicyList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]OSIndexPolicy, len(*in))
+		*out = make([]ClusterOSIndexPolicy, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OSIndexPolicyList.
-func (in *OSIndexPolicyList) DeepCopy() *OSIndexPolicyList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterOSIndexPolicyList.
+func (in *ClusterOSIndexPolicyList) DeepCopy() *ClusterOSIndexPolicyList {
 	if in == nil {
 		return nil
 	}
-	out := new(OSIndexPolicyList)
+	out := new(ClusterOSIndexPolicyList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *OSIndexPolicyList) DeepCopyObject() runtime.Object {
+func (in *ClusterOSIndexPolicyList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -324,212 +339,1058 @@ func (in *OSIndexPolicyList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OSIndexPolicySpec) DeepCopyInto(out *OSIndexPolicySpec) {
+func (in *ConvertIndexToRemoteAction) DeepCopyInto(out *ConvertIndexToRemoteAction) {
 	*out = *in
-	out.OpensearhConnection = in.OpensearhConnection
-	in.Policy.DeepCopyInto(&out.Policy)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OSIndexPolicySpec.
-func (in *OSIndexPolicySpec) DeepCopy() *OSIndexPolicySpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConvertIndexToRemoteAction.
+func (in *ConvertIndexToRemoteAction) DeepCopy() *ConvertIndexToRemoteAction {
 	if in == nil {
 		return nil
 	}
-	out := new(OSIndexPolicySpec)
+	out := new(ConvertIndexToRemoteAction)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OSIndexPolicyStatus) DeepCopyInto(out *OSIndexPolicyStatus) {
+func (in *DeleteAction) DeepCopyInto(out *DeleteAction) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OSIndexPolicyStatus.
-func (in *OSIndexPolicyStatus) DeepCopy() *OSIndexPolicyStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeleteAction.
+func (in *DeleteAction) DeepCopy() *DeleteAction {
 	if in == nil {
 		return nil
 	}
-	out := new(OSIndexPolicyStatus)
+	out := new(DeleteAction)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OpenAction) DeepCopyInto(out *OpenAction) {
+func (in *FailedIndex) DeepCopyInto(out *FailedIndex) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenAction.
-func (in *OpenAction) DeepCopy() *OpenAction {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FailedIndex.
+func (in *FailedIndex) DeepCopy() *FailedIndex {
 	if in == nil {
 		return nil
 	}
-	out := new(OpenAction)
+	out := new(FailedIndex)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OpensearchIndexPolicy) DeepCopyInto(out *OpensearchIndexPolicy) {
+func (in *ForceMerge) DeepCopyInto(out *ForceMerge) {
 	*out = *in
-	if in.ErrorNotification != nil {
-		in, out := &in.ErrorNotification, &out.ErrorNotification
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	if in.States != nil {
-		in, out := &in.States, &out.States
-		*out = make([]*State, len(*in))
-		for i := range *in {
-			if (*in)[i] != nil {
-				in, out := &(*in)[i], &(*out)[i]
-				*out = new(State)
-				(*in).DeepCopyInto(*out)
-			}
-		}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ForceMerge.
+func (in *ForceMerge) DeepCopy() *ForceMerge {
+	if in == nil {
+		return nil
 	}
-	if in.ISMTemplate != nil {
-		in, out := &in.ISMTemplate, &out.ISMTemplate
-		*out = new(ISMTemplate)
-		(*in).DeepCopyInto(*out)
+	out := new(ForceMerge)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ForceMergeAction) DeepCopyInto(out *ForceMergeAction) {
+	*out = *in
+	if in.ForceMerge != nil {
+		in, out := &in.ForceMerge, &out.ForceMerge
+		*out = new(ForceMerge)
+		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchIndexPolicy.
-func (in *OpensearchIndexPolicy) DeepCopy() *OpensearchIndexPolicy {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ForceMergeAction.
+func (in *ForceMergeAction) DeepCopy() *ForceMergeAction {
 	if in == nil {
 		return nil
 	}
-	out := new(OpensearchIndexPolicy)
+	out := new(ForceMergeAction)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OpensearhConnection) DeepCopyInto(out *OpensearhConnection) {
+func (in *ISMTemplate) DeepCopyInto(out *ISMTemplate) {
 	*out = *in
+	if in.IndexPatterns != nil {
+		in, out := &in.IndexPatterns, &out.IndexPatterns
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearhConnection.
-func (in *OpensearhConnection) DeepCopy() *OpensearhConnection {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ISMTemplate.
+func (in *ISMTemplate) DeepCopy() *ISMTemplate {
 	if in == nil {
 		return nil
 	}
-	out := new(OpensearhConnection)
+	out := new(ISMTemplate)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ReadOnlyAction) DeepCopyInto(out *ReadOnlyAction) {
+func (in *IndexPriorityAction) DeepCopyInto(out *IndexPriorityAction) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReadOnlyAction.
-func (in *ReadOnlyAction) DeepCopy() *ReadOnlyAction {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IndexPriorityAction.
+func (in *IndexPriorityAction) DeepCopy() *IndexPriorityAction {
 	if in == nil {
 		return nil
 	}
-	out := new(ReadOnlyAction)
+	out := new(IndexPriorityAction)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ReadWriteAction) DeepCopyInto(out *ReadWriteAction) {
+func (in *IndexRetryStatus) DeepCopyInto(out *IndexRetryStatus) {
 	*out = *in
+	in.LastAttemptTime.DeepCopyInto(&out.LastAttemptTime)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReadWriteAction.
-func (in *ReadWriteAction) DeepCopy() *ReadWriteAction {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IndexRetryStatus.
+func (in *IndexRetryStatus) DeepCopy() *IndexRetryStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(ReadWriteAction)
+	out := new(IndexRetryStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ReplicaCountAction) DeepCopyInto(out *ReplicaCountAction) {
+func (in *ManagedIndexStateStatus) DeepCopyInto(out *ManagedIndexStateStatus) {
 	*out = *in
+	if in.OldestSince != nil {
+		in, out := &in.OldestSince, &out.OldestSince
+		*out = (*in).DeepCopy()
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicaCountAction.
-func (in *ReplicaCountAction) DeepCopy() *ReplicaCountAction {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedIndexStateStatus.
+func (in *ManagedIndexStateStatus) DeepCopy() *ManagedIndexStateStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(ReplicaCountAction)
+	out := new(ManagedIndexStateStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RollOverAction) DeepCopyInto(out *RollOverAction) {
+func (in *ManagedIndicesStatus) DeepCopyInto(out *ManagedIndicesStatus) {
 	*out = *in
+	if in.States != nil {
+		in, out := &in.States, &out.States
+		*out = make([]ManagedIndexStateStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.FailedIndices != nil {
+		in, out := &in.FailedIndices, &out.FailedIndices
+		*out = make([]FailedIndex, len(*in))
+		copy(*out, *in)
+	}
+	in.LastExplainTime.DeepCopyInto(&out.LastExplainTime)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RollOverAction.
-func (in *RollOverAction) DeepCopy() *RollOverAction {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedIndicesStatus.
+func (in *ManagedIndicesStatus) DeepCopy() *ManagedIndicesStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(RollOverAction)
+	out := new(ManagedIndicesStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RollupAction) DeepCopyInto(out *RollupAction) {
+func (in *NotifyAction) DeepCopyInto(out *NotifyAction) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RollupAction.
-func (in *RollupAction) DeepCopy() *RollupAction {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotifyAction.
+func (in *NotifyAction) DeepCopy() *NotifyAction {
 	if in == nil {
 		return nil
 	}
-	out := new(RollupAction)
+	out := new(NotifyAction)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ShrinkAction) DeepCopyInto(out *ShrinkAction) {
+func (in *OSIndexPolicy) DeepCopyInto(out *OSIndexPolicy) {
 	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ShrinkAction.
-func (in *ShrinkAction) DeepCopy() *ShrinkAction {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OSIndexPolicy.
+func (in *OSIndexPolicy) DeepCopy() *OSIndexPolicy {
 	if in == nil {
 		return nil
 	}
-	out := new(ShrinkAction)
+	out := new(OSIndexPolicy)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OSIndexPolicy) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SnapshotAction) DeepCopyInto(out *SnapshotAction) {
+func (in *OSIndexPolicyGuardrail) DeepCopyInto(out *OSIndexPolicyGuardrail) {
 	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SnapshotAction.
-func (in *SnapshotAction) DeepCopy() *SnapshotAction {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OSIndexPolicyGuardrail.
+func (in *OSIndexPolicyGuardrail) DeepCopy() *OSIndexPolicyGuardrail {
 	if in == nil {
 		return nil
 	}
-	out := new(SnapshotAction)
+	out := new(OSIndexPolicyGuardrail)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OSIndexPolicyGuardrail) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *State) DeepCopyInto(out *State) {
+func (in *OSIndexPolicyGuardrailList) DeepCopyInto(out *OSIndexPolicyGuardrailList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]OSIndexPolicyGuardrail, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OSIndexPolicyGuardrailList.
+func (in *OSIndexPolicyGuardrailList) DeepCopy() *OSIndexPolicyGuardrailList {
+	if in == nil {
+		return nil
+	}
+	out := new(OSIndexPolicyGuardrailList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OSIndexPolicyGuardrailList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OSIndexPolicyGuardrailSpec) DeepCopyInto(out *OSIndexPolicyGuardrailSpec) {
+	*out = *in
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AllowedClusters != nil {
+		in, out := &in.AllowedClusters, &out.AllowedClusters
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AllowedIndexPatternPrefixes != nil {
+		in, out := &in.AllowedIndexPatternPrefixes, &out.AllowedIndexPatternPrefixes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OSIndexPolicyGuardrailSpec.
+func (in *OSIndexPolicyGuardrailSpec) DeepCopy() *OSIndexPolicyGuardrailSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OSIndexPolicyGuardrailSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OSIndexPolicyList) DeepCopyInto(out *OSIndexPolicyList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]OSIndexPolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OSIndexPolicyList.
+func (in *OSIndexPolicyList) DeepCopy() *OSIndexPolicyList {
+	if in == nil {
+		return nil
+	}
+	out := new(OSIndexPolicyList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OSIndexPolicyList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OSIndexPolicySpec) DeepCopyInto(out *OSIndexPolicySpec) {
+	*out = *in
+	in.OpensearhConnection.DeepCopyInto(&out.OpensearhConnection)
+	in.Policy.DeepCopyInto(&out.Policy)
+	if in.AutoRetry != nil {
+		in, out := &in.AutoRetry, &out.AutoRetry
+		*out = new(AutoRetry)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Rollout != nil {
+		in, out := &in.Rollout, &out.Rollout
+		*out = new(Rollout)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AttachExisting != nil {
+		in, out := &in.AttachExisting, &out.AttachExisting
+		*out = new(AttachExisting)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ResyncInterval != nil {
+		in, out := &in.ResyncInterval, &out.ResyncInterval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.TemplateRef != nil {
+		in, out := &in.TemplateRef, &out.TemplateRef
+		*out = new(PolicyTemplateRef)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Base != nil {
+		in, out := &in.Base, &out.Base
+		*out = new(PolicyBaseRef)
+		**out = **in
+	}
+	if in.PolicyFrom != nil {
+		in, out := &in.PolicyFrom, &out.PolicyFrom
+		*out = new(PolicySource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Targets != nil {
+		in, out := &in.Targets, &out.Targets
+		*out = new(PolicyTargets)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OSIndexPolicySpec.
+func (in *OSIndexPolicySpec) DeepCopy() *OSIndexPolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OSIndexPolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OSIndexPolicyStatus) DeepCopyInto(out *OSIndexPolicyStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ManagedIndices != nil {
+		in, out := &in.ManagedIndices, &out.ManagedIndices
+		*out = new(ManagedIndicesStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Rollout != nil {
+		in, out := &in.Rollout, &out.Rollout
+		*out = new(RolloutStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RetryAttempts != nil {
+		in, out := &in.RetryAttempts, &out.RetryAttempts
+		*out = make([]IndexRetryStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.AttachExisting != nil {
+		in, out := &in.AttachExisting, &out.AttachExisting
+		*out = new(AttachExistingStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Plan != nil {
+		in, out := &in.Plan, &out.Plan
+		*out = new(PlanStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.EffectiveIndexPatterns != nil {
+		in, out := &in.EffectiveIndexPatterns, &out.EffectiveIndexPatterns
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ResolvedPolicy != nil {
+		in, out := &in.ResolvedPolicy, &out.ResolvedPolicy
+		*out = new(OpensearchIndexPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SyncedPolicy != nil {
+		in, out := &in.SyncedPolicy, &out.SyncedPolicy
+		*out = new(SyncedPolicy)
+		**out = **in
+	}
+	if in.Targets != nil {
+		in, out := &in.Targets, &out.Targets
+		*out = make([]TargetStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OSIndexPolicyStatus.
+func (in *OSIndexPolicyStatus) DeepCopy() *OSIndexPolicyStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OSIndexPolicyStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OSIndexPolicyTemplate) DeepCopyInto(out *OSIndexPolicyTemplate) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OSIndexPolicyTemplate.
+func (in *OSIndexPolicyTemplate) DeepCopy() *OSIndexPolicyTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(OSIndexPolicyTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OSIndexPolicyTemplate) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OSIndexPolicyTemplateList) DeepCopyInto(out *OSIndexPolicyTemplateList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]OSIndexPolicyTemplate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OSIndexPolicyTemplateList.
+func (in *OSIndexPolicyTemplateList) DeepCopy() *OSIndexPolicyTemplateList {
+	if in == nil {
+		return nil
+	}
+	out := new(OSIndexPolicyTemplateList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OSIndexPolicyTemplateList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OSIndexPolicyTemplateSpec) DeepCopyInto(out *OSIndexPolicyTemplateSpec) {
+	*out = *in
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make([]TemplateParameter, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.Policy.DeepCopyInto(&out.Policy)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OSIndexPolicyTemplateSpec.
+func (in *OSIndexPolicyTemplateSpec) DeepCopy() *OSIndexPolicyTemplateSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OSIndexPolicyTemplateSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpenAction) DeepCopyInto(out *OpenAction) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenAction.
+func (in *OpenAction) DeepCopy() *OpenAction {
+	if in == nil {
+		return nil
+	}
+	out := new(OpenAction)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpenSearchCluster) DeepCopyInto(out *OpenSearchCluster) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenSearchCluster.
+func (in *OpenSearchCluster) DeepCopy() *OpenSearchCluster {
+	if in == nil {
+		return nil
+	}
+	out := new(OpenSearchCluster)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OpenSearchCluster) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpenSearchClusterList) DeepCopyInto(out *OpenSearchClusterList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]OpenSearchCluster, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenSearchClusterList.
+func (in *OpenSearchClusterList) DeepCopy() *OpenSearchClusterList {
+	if in == nil {
+		return nil
+	}
+	out := new(OpenSearchClusterList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OpenSearchClusterList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpenSearchClusterSpec) DeepCopyInto(out *OpenSearchClusterSpec) {
+	*out = *in
+	in.OpensearhConnection.DeepCopyInto(&out.OpensearhConnection)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenSearchClusterSpec.
+func (in *OpenSearchClusterSpec) DeepCopy() *OpenSearchClusterSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OpenSearchClusterSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpensearchAuth) DeepCopyInto(out *OpensearchAuth) {
+	*out = *in
+	if in.Basic != nil {
+		in, out := &in.Basic, &out.Basic
+		*out = new(BasicAuth)
+		**out = **in
+	}
+	if in.BearerTokenSecretRef != nil {
+		in, out := &in.BearerTokenSecretRef, &out.BearerTokenSecretRef
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.APIKeySecretRef != nil {
+		in, out := &in.APIKeySecretRef, &out.APIKeySecretRef
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ClientCertificate != nil {
+		in, out := &in.ClientCertificate, &out.ClientCertificate
+		*out = new(ClientCertificateAuth)
+		**out = **in
+	}
+	if in.AWS != nil {
+		in, out := &in.AWS, &out.AWS
+		*out = new(AWSAuth)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchAuth.
+func (in *OpensearchAuth) DeepCopy() *OpensearchAuth {
+	if in == nil {
+		return nil
+	}
+	out := new(OpensearchAuth)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpensearchIndexPolicy) DeepCopyInto(out *OpensearchIndexPolicy) {
+	*out = *in
+	if in.ErrorNotification != nil {
+		in, out := &in.ErrorNotification, &out.ErrorNotification
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.States != nil {
+		in, out := &in.States, &out.States
+		*out = make([]*State, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(State)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+	if in.ISMTemplate != nil {
+		in, out := &in.ISMTemplate, &out.ISMTemplate
+		*out = new(ISMTemplate)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchIndexPolicy.
+func (in *OpensearchIndexPolicy) DeepCopy() *OpensearchIndexPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(OpensearchIndexPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpensearchSniffing) DeepCopyInto(out *OpensearchSniffing) {
+	*out = *in
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchSniffing.
+func (in *OpensearchSniffing) DeepCopy() *OpensearchSniffing {
+	if in == nil {
+		return nil
+	}
+	out := new(OpensearchSniffing)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpensearchTLS) DeepCopyInto(out *OpensearchTLS) {
+	*out = *in
+	if in.CASecretRef != nil {
+		in, out := &in.CASecretRef, &out.CASecretRef
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchTLS.
+func (in *OpensearchTLS) DeepCopy() *OpensearchTLS {
+	if in == nil {
+		return nil
+	}
+	out := new(OpensearchTLS)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpensearhConnection) DeepCopyInto(out *OpensearhConnection) {
+	*out = *in
+	if in.URLs != nil {
+		in, out := &in.URLs, &out.URLs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Sniffing != nil {
+		in, out := &in.Sniffing, &out.Sniffing
+		*out = new(OpensearchSniffing)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CredentialsSecretRef != nil {
+		in, out := &in.CredentialsSecretRef, &out.CredentialsSecretRef
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(OpensearchTLS)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Auth != nil {
+		in, out := &in.Auth, &out.Auth
+		*out = new(OpensearchAuth)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearhConnection.
+func (in *OpensearhConnection) DeepCopy() *OpensearhConnection {
+	if in == nil {
+		return nil
+	}
+	out := new(OpensearhConnection)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PlanStatus) DeepCopyInto(out *PlanStatus) {
+	*out = *in
+	if in.Diff != nil {
+		in, out := &in.Diff, &out.Diff
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.PlanTime.DeepCopyInto(&out.PlanTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlanStatus.
+func (in *PlanStatus) DeepCopy() *PlanStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PlanStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PolicyBaseRef) DeepCopyInto(out *PolicyBaseRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PolicyBaseRef.
+func (in *PolicyBaseRef) DeepCopy() *PolicyBaseRef {
+	if in == nil {
+		return nil
+	}
+	out := new(PolicyBaseRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PolicySource) DeepCopyInto(out *PolicySource) {
+	*out = *in
+	in.ConfigMapKeyRef.DeepCopyInto(&out.ConfigMapKeyRef)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PolicySource.
+func (in *PolicySource) DeepCopy() *PolicySource {
+	if in == nil {
+		return nil
+	}
+	out := new(PolicySource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PolicyTargets) DeepCopyInto(out *PolicyTargets) {
+	*out = *in
+	if in.Clusters != nil {
+		in, out := &in.Clusters, &out.Clusters
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PolicyTargets.
+func (in *PolicyTargets) DeepCopy() *PolicyTargets {
+	if in == nil {
+		return nil
+	}
+	out := new(PolicyTargets)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PolicyTemplateRef) DeepCopyInto(out *PolicyTemplateRef) {
+	*out = *in
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PolicyTemplateRef.
+func (in *PolicyTemplateRef) DeepCopy() *PolicyTemplateRef {
+	if in == nil {
+		return nil
+	}
+	out := new(PolicyTemplateRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReadOnlyAction) DeepCopyInto(out *ReadOnlyAction) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReadOnlyAction.
+func (in *ReadOnlyAction) DeepCopy() *ReadOnlyAction {
+	if in == nil {
+		return nil
+	}
+	out := new(ReadOnlyAction)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReadWriteAction) DeepCopyInto(out *ReadWriteAction) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReadWriteAction.
+func (in *ReadWriteAction) DeepCopy() *ReadWriteAction {
+	if in == nil {
+		return nil
+	}
+	out := new(ReadWriteAction)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReplicaCountAction) DeepCopyInto(out *ReplicaCountAction) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicaCountAction.
+func (in *ReplicaCountAction) DeepCopy() *ReplicaCountAction {
+	if in == nil {
+		return nil
+	}
+	out := new(ReplicaCountAction)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RollOverAction) DeepCopyInto(out *RollOverAction) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RollOverAction.
+func (in *RollOverAction) DeepCopy() *RollOverAction {
+	if in == nil {
+		return nil
+	}
+	out := new(RollOverAction)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Rollout) DeepCopyInto(out *Rollout) {
+	*out = *in
+	if in.Include != nil {
+		in, out := &in.Include, &out.Include
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.StateMappings != nil {
+		in, out := &in.StateMappings, &out.StateMappings
+		*out = make([]StateMapping, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Rollout.
+func (in *Rollout) DeepCopy() *Rollout {
+	if in == nil {
+		return nil
+	}
+	out := new(Rollout)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutStatus) DeepCopyInto(out *RolloutStatus) {
+	*out = *in
+	if in.PendingIndices != nil {
+		in, out := &in.PendingIndices, &out.PendingIndices
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.StartTime.DeepCopyInto(&out.StartTime)
+	if in.FailedIndices != nil {
+		in, out := &in.FailedIndices, &out.FailedIndices
+		*out = make([]FailedIndex, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutStatus.
+func (in *RolloutStatus) DeepCopy() *RolloutStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RollupAction) DeepCopyInto(out *RollupAction) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RollupAction.
+func (in *RollupAction) DeepCopy() *RollupAction {
+	if in == nil {
+		return nil
+	}
+	out := new(RollupAction)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ShrinkAction) DeepCopyInto(out *ShrinkAction) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ShrinkAction.
+func (in *ShrinkAction) DeepCopy() *ShrinkAction {
+	if in == nil {
+		return nil
+	}
+	out := new(ShrinkAction)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SnapshotAction) DeepCopyInto(out *SnapshotAction) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SnapshotAction.
+func (in *SnapshotAction) DeepCopy() *SnapshotAction {
+	if in == nil {
+		return nil
+	}
+	out := new(SnapshotAction)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *State) DeepCopyInto(out *State) {
 	*out = *in
 	if in.Actions != nil {
 		in, out := &in.Actions, &out.Actions
@@ -565,6 +1426,21 @@ func (in *State) DeepCopy() *State {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StateMapping) DeepCopyInto(out *StateMapping) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StateMapping.
+func (in *StateMapping) DeepCopy() *StateMapping {
+	if in == nil {
+		return nil
+	}
+	out := new(StateMapping)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StopReplicationAction) DeepCopyInto(out *StopReplicationAction) {
 	*out = *in
@@ -580,6 +1456,95 @@ func (in *StopReplicationAction) DeepCopy() *StopReplicationAction {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SyncedPolicy) DeepCopyInto(out *SyncedPolicy) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SyncedPolicy.
+func (in *SyncedPolicy) DeepCopy() *SyncedPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(SyncedPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TargetStatus) DeepCopyInto(out *TargetStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ManagedIndices != nil {
+		in, out := &in.ManagedIndices, &out.ManagedIndices
+		*out = new(ManagedIndicesStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Rollout != nil {
+		in, out := &in.Rollout, &out.Rollout
+		*out = new(RolloutStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RetryAttempts != nil {
+		in, out := &in.RetryAttempts, &out.RetryAttempts
+		*out = make([]IndexRetryStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.AttachExisting != nil {
+		in, out := &in.AttachExisting, &out.AttachExisting
+		*out = new(AttachExistingStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Plan != nil {
+		in, out := &in.Plan, &out.Plan
+		*out = new(PlanStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SyncedPolicy != nil {
+		in, out := &in.SyncedPolicy, &out.SyncedPolicy
+		*out = new(SyncedPolicy)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TargetStatus.
+func (in *TargetStatus) DeepCopy() *TargetStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TargetStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TemplateParameter) DeepCopyInto(out *TemplateParameter) {
+	*out = *in
+	if in.Default != nil {
+		in, out := &in.Default, &out.Default
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TemplateParameter.
+func (in *TemplateParameter) DeepCopy() *TemplateParameter {
+	if in == nil {
+		return nil
+	}
+	out := new(TemplateParameter)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Transition) DeepCopyInto(out *Transition) {
 	*out = *in
@@ -0,0 +1,173 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Command ism-import writes an OSIndexPolicy manifest for every ISM policy of
+// an OpenSearch cluster, to bring policies created by hand under the operator.
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/yaml"
+
+	batchv1 "github.com/a8uhnf/opensearch-ism-crd/api/v1"
+	"github.com/a8uhnf/opensearch-ism-crd/internal/pkg/opensearch"
+)
+
+// invalidNameChars are the characters a policy ID may contain but an object name may not.
+var invalidNameChars = regexp.MustCompile(`[^a-z0-9.-]+`)
+
+func main() {
+	var url, username, namespace, credentialsSecret, adoptionPolicy, outputDir string
+	var insecureSkipVerify bool
+	flag.StringVar(&url, "url", "", "URL of the OpenSearch cluster to import the policies of.")
+	flag.StringVar(&username, "username", "", "Username for basic authentication. "+
+		"The password is read from the OPENSEARCH_PASSWORD environment variable.")
+	flag.BoolVar(&insecureSkipVerify, "insecure-skip-verify", false, "Skip verification of the cluster certificate.")
+	flag.StringVar(&namespace, "namespace", "default", "Namespace of the generated OSIndexPolicy objects.")
+	flag.StringVar(&credentialsSecret, "credentials-secret", "",
+		"Secret holding the cluster credentials, referenced by the generated objects.")
+	flag.StringVar(&adoptionPolicy, "adoption-policy", batchv1.AdoptionAdopt,
+		"adoption_policy of the generated objects. Adopt lets them take over the imported policies.")
+	flag.StringVar(&outputDir, "output-dir", ".", "Directory the manifests are written to, one file per policy.")
+	opts := zap.Options{}
+	opts.BindFlags(flag.CommandLine)
+	flag.Parse()
+	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
+
+	if url == "" {
+		fmt.Fprintln(os.Stderr, "--url is required")
+		os.Exit(2)
+	}
+	ctx := context.Background()
+	client, err := opensearch.NewOpenSearchClient(ctx, opensearch.OpenSearchConfig{
+		URL:      url,
+		Username: username,
+		Password: os.Getenv("OPENSEARCH_PASSWORD"),
+		TLSConfig: &http.Transport{
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: insecureSkipVerify}, // nolint:gosec
+		},
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to create OpenSearch client: %v\n", err)
+		os.Exit(1)
+	}
+	policies, err := client.ListIndexPolicies(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to list index policies: %v\n", err)
+		os.Exit(1)
+	}
+	if err := os.MkdirAll(outputDir, 0o755); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to create %s: %v\n", outputDir, err)
+		os.Exit(1)
+	}
+
+	failed := false
+	for _, policy := range policies {
+		connection := batchv1.OpensearhConnection{URL: url}
+		if credentialsSecret != "" {
+			connection.CredentialsSecretRef = &corev1.LocalObjectReference{Name: credentialsSecret}
+		}
+		manifest, dropped, err := manifest(policy, namespace, adoptionPolicy, connection)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", policy.ID, err)
+			failed = true
+			continue
+		}
+		path := filepath.Join(outputDir, objectName(policy.ID)+".yaml")
+		if err := os.WriteFile(path, manifest, 0o644); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to write %s: %v\n", path, err)
+			failed = true
+			continue
+		}
+		fmt.Printf("%s: wrote %s\n", policy.ID, path)
+		if len(dropped) > 0 {
+			fmt.Fprintf(os.Stderr, "%s: fields not supported by OSIndexPolicy were dropped: %s\n", policy.ID, strings.Join(dropped, ", "))
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// manifest renders the OSIndexPolicy for an imported policy. The fields the API
+// cannot represent are listed in a comment on top.
+func manifest(policy opensearch.IndexPolicy, namespace, adoptionPolicy string, connection batchv1.OpensearhConnection) ([]byte, []string, error) {
+	spec, dropped, err := opensearch.ImportPolicy(policy.Policy)
+	if err != nil {
+		return nil, nil, err
+	}
+	object := &batchv1.OSIndexPolicy{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: batchv1.GroupVersion.String(),
+			Kind:       "OSIndexPolicy",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      objectName(policy.ID),
+			Namespace: namespace,
+		},
+		Spec: batchv1.OSIndexPolicySpec{
+			PolicyID:            policy.ID,
+			OpensearhConnection: connection,
+			Policy:              *spec,
+			AdoptionPolicy:      adoptionPolicy,
+		},
+	}
+	// Drop the empty status and creation timestamp the API types always render.
+	raw, err := json.Marshal(object)
+	if err != nil {
+		return nil, nil, err
+	}
+	var document map[string]interface{}
+	if err := json.Unmarshal(raw, &document); err != nil {
+		return nil, nil, err
+	}
+	delete(document, "status")
+	delete(document["metadata"].(map[string]interface{}), "creationTimestamp")
+	out, err := yaml.Marshal(document)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var header strings.Builder
+	fmt.Fprintf(&header, "# Imported from ISM policy %s.\n", policy.ID)
+	if len(dropped) > 0 {
+		header.WriteString("# These fields are not supported by OSIndexPolicy and were dropped:\n")
+		for _, field := range dropped {
+			fmt.Fprintf(&header, "#   %s\n", field)
+		}
+	}
+	return append([]byte(header.String()), out...), dropped, nil
+}
+
+// objectName turns a policy ID into a valid object name.
+func objectName(policyID string) string {
+	name := invalidNameChars.ReplaceAllString(strings.ToLower(policyID), "-")
+	return strings.Trim(name, "-.")
+}
@@ -21,6 +21,7 @@ import (
 	"flag"
 	"os"
 	"path/filepath"
+	"time"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
@@ -39,6 +40,7 @@ import (
 
 	batchv1 "github.com/a8uhnf/opensearch-ism-crd/api/v1"
 	"github.com/a8uhnf/opensearch-ism-crd/internal/controller"
+	"github.com/a8uhnf/opensearch-ism-crd/internal/pkg/opensearch"
 	webhookv1 "github.com/a8uhnf/opensearch-ism-crd/internal/webhook/v1"
 	// +kubebuilder:scaffold:imports
 )
@@ -65,6 +67,13 @@ func main() {
 	var secureMetrics bool
 	var enableHTTP2 bool
 	var tlsOpts []func(*tls.Config)
+	var explainInterval time.Duration
+	var dryRun bool
+	var clusterSecretNamespace string
+	var namespacePrefix bool
+	var resyncInterval, errorBackoff, maxErrorBackoff time.Duration
+	retryConfig := opensearch.DefaultRetryConfig()
+	breakerConfig := opensearch.DefaultBreakerConfig()
 	flag.StringVar(&metricsAddr, "metrics-bind-address", "0", "The address the metrics endpoint binds to. "+
 		"Use :8443 for HTTPS or :8080 for HTTP, or leave as 0 to disable the metrics service.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
@@ -82,6 +91,30 @@ func main() {
 	flag.StringVar(&metricsCertKey, "metrics-cert-key", "tls.key", "The name of the metrics server key file.")
 	flag.BoolVar(&enableHTTP2, "enable-http2", false,
 		"If set, HTTP/2 will be enabled for the metrics and webhook servers")
+	flag.IntVar(&retryConfig.MaxRetries, "opensearch-max-retries", retryConfig.MaxRetries,
+		"Number of retries of idempotent OpenSearch requests on transient failures.")
+	flag.DurationVar(&retryConfig.InitialBackoff, "opensearch-retry-initial-backoff", retryConfig.InitialBackoff,
+		"Base wait before retrying an OpenSearch request, doubled (with jitter) on every retry.")
+	flag.DurationVar(&retryConfig.MaxBackoff, "opensearch-retry-max-backoff", retryConfig.MaxBackoff,
+		"Maximum wait between two attempts of an OpenSearch request.")
+	flag.IntVar(&breakerConfig.FailureThreshold, "opensearch-breaker-failure-threshold", breakerConfig.FailureThreshold,
+		"Consecutive failed OpenSearch calls that open a cluster's circuit breaker. Set to 0 to disable.")
+	flag.DurationVar(&breakerConfig.OpenDuration, "opensearch-breaker-open-duration", breakerConfig.OpenDuration,
+		"How long an open circuit breaker short-circuits reconciles of a cluster.")
+	flag.DurationVar(&explainInterval, "explain-interval", time.Minute,
+		"Minimum time between two ISM explain sweeps of the indices managed by a policy.")
+	flag.DurationVar(&resyncInterval, "resync-interval", 5*time.Minute,
+		"Time between two drift checks of a policy in OpenSearch. Policies can override it with spec.resync_interval.")
+	flag.DurationVar(&errorBackoff, "error-backoff", time.Second,
+		"Initial wait before retrying a failed reconcile, doubled on every consecutive failure.")
+	flag.DurationVar(&maxErrorBackoff, "max-error-backoff", 5*time.Minute,
+		"Maximum wait before retrying a failed reconcile.")
+	flag.BoolVar(&dryRun, "dry-run", false,
+		"Plan the changes to OpenSearch of every OSIndexPolicy and report them in status and events, without making them.")
+	flag.StringVar(&clusterSecretNamespace, "cluster-secret-namespace", "",
+		"The namespace the Secrets and templates referenced by ClusterOSIndexPolicies are read from.")
+	flag.BoolVar(&namespacePrefix, "namespace-prefix", false,
+		"Prefix the ISM policy ID and index patterns of every OSIndexPolicy with its namespace, e.g. team-a-logs-retention.")
 	opts := zap.Options{
 		Development: true,
 	}
@@ -203,19 +236,42 @@ func main() {
 		os.Exit(1)
 	}
 
-	if err := (&controller.OSIndexPolicyReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
-	}).SetupWithManager(mgr); err != nil {
+	clientCache := opensearch.NewClientCache()
+	clientCache.Retry = retryConfig
+	clientCache.Breaker = breakerConfig
+	policyReconciler := &controller.OSIndexPolicyReconciler{
+		Client:                 mgr.GetClient(),
+		Scheme:                 mgr.GetScheme(),
+		Clients:                clientCache,
+		Recorder:               mgr.GetEventRecorderFor("osindexpolicy-controller"),
+		ExplainInterval:        explainInterval,
+		DryRun:                 dryRun,
+		ResyncInterval:         resyncInterval,
+		ErrorBackoff:           errorBackoff,
+		MaxErrorBackoff:        maxErrorBackoff,
+		NamespacePrefix:        namespacePrefix,
+		ClusterSecretNamespace: clusterSecretNamespace,
+	}
+	if err := policyReconciler.SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "OSIndexPolicy")
 		os.Exit(1)
 	}
+	if err := (&controller.ClusterOSIndexPolicyReconciler{
+		OSIndexPolicyReconciler: policyReconciler,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ClusterOSIndexPolicy")
+		os.Exit(1)
+	}
 	// nolint:goconst
 	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
-		if err := webhookv1.SetupOSIndexPolicyWebhookWithManager(mgr); err != nil {
+		if err := webhookv1.SetupOSIndexPolicyWebhookWithManager(mgr, namespacePrefix); err != nil {
 			setupLog.Error(err, "unable to create webhook", "webhook", "OSIndexPolicy")
 			os.Exit(1)
 		}
+		if err := webhookv1.SetupClusterOSIndexPolicyWebhookWithManager(mgr, clusterSecretNamespace); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "ClusterOSIndexPolicy")
+			os.Exit(1)
+		}
 	}
 	// +kubebuilder:scaffold:builder
 
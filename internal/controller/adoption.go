@@ -0,0 +1,82 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	batchv1 "github.com/a8uhnf/opensearch-ism-crd/api/v1"
+	"github.com/a8uhnf/opensearch-ism-crd/internal/pkg/opensearch"
+)
+
+const (
+	eventAdopted         = "Adopted"
+	eventAdoptionRefused = "AdoptionRefused"
+)
+
+// ownsPolicy reports whether the ISM policy found in OpenSearch belongs to the
+// object. A policy without owner marker belongs to the object only when it is
+// the very version the object last synced, under its current policy ID.
+func ownsPolicy(policy batchv1.IndexPolicyObject, remotePolicy *opensearch.IndexPolicy) bool {
+	owner := opensearch.PolicyOwner(remotePolicy.Policy)
+	if owner != "" {
+		return owner == policyKey(policy)
+	}
+	synced := policy.GetStatus().SyncedPolicy
+	return synced != nil && synced.PolicyID == remotePolicy.ID &&
+		synced.SeqNo == remotePolicy.SeqNo && synced.PrimaryTerm == remotePolicy.PrimaryTerm && synced.PrimaryTerm > 0
+}
+
+// claimPolicy decides whether the reconciler may manage the ISM policy found
+// in OpenSearch, following spec.adoption_policy. When the policy may not be
+// managed, the Synced condition says why.
+func (r *OSIndexPolicyReconciler) claimPolicy(ctx context.Context, policy batchv1.IndexPolicyObject, remotePolicy *opensearch.IndexPolicy) bool {
+	if ownsPolicy(policy, remotePolicy) {
+		return true
+	}
+	owner := opensearch.PolicyOwner(remotePolicy.Policy)
+
+	adoption := policy.GetSpec().AdoptionPolicy
+	if adoption == "" {
+		adoption = batchv1.AdoptionFail
+	}
+	logr := logf.FromContext(ctx)
+	switch {
+	case adoption == batchv1.AdoptionOverwrite && owner != "":
+		logr.Info("Taking over index policy owned by another object", "policyName", policy.GetName(), "owner", owner)
+		r.Recorder.Eventf(policy, corev1.EventTypeWarning, eventAdopted, "Took over index policy %s from %s", policy.GetSpec().PolicyID, owner)
+		return true
+	case adoption != batchv1.AdoptionFail && owner == "":
+		logr.Info("Adopting existing index policy", "policyName", policy.GetName())
+		r.Recorder.Eventf(policy, corev1.EventTypeNormal, eventAdopted, "Adopted existing index policy %s", policy.GetSpec().PolicyID)
+		return true
+	}
+
+	message := fmt.Sprintf("index policy %s already exists in OpenSearch and is not managed by this object, set adoption_policy to Adopt to take it over", policy.GetSpec().PolicyID)
+	if owner != "" {
+		message = fmt.Sprintf("index policy %s is managed by %s, set adoption_policy to Overwrite to take it over", policy.GetSpec().PolicyID, owner)
+	}
+	logr.Info("Index policy not owned, skipping sync", "policyName", policy.GetName(), "owner", owner, "adoptionPolicy", adoption)
+	setSynced(policy, metav1.ConditionFalse, "NotOwned", message)
+	r.Recorder.Event(policy, corev1.EventTypeWarning, eventAdoptionRefused, message)
+	return false
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	batchv1 "github.com/a8uhnf/opensearch-ism-crd/api/v1"
+	"github.com/a8uhnf/opensearch-ism-crd/internal/pkg/opensearch"
+)
+
+var _ = Describe("Adoption of existing policies", func() {
+	remote := func(description string) *opensearch.IndexPolicy {
+		raw, err := json.Marshal(map[string]string{"description": description})
+		Expect(err).NotTo(HaveOccurred())
+		return &opensearch.IndexPolicy{ID: "logs", SeqNo: 5, PrimaryTerm: 1, Policy: raw}
+	}
+
+	DescribeTable("decides whether the policy may be managed",
+		func(adoption string, description string, synced *batchv1.SyncedPolicy, want bool) {
+			reconciler := &OSIndexPolicyReconciler{Recorder: record.NewFakeRecorder(10)}
+			policy := &batchv1.OSIndexPolicy{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "logs"},
+				Spec:       batchv1.OSIndexPolicySpec{PolicyID: "logs", AdoptionPolicy: adoption},
+			}
+			setSynced(policy, metav1.ConditionTrue, "InSync", "")
+			policy.Status.SyncedPolicy = synced
+
+			Expect(reconciler.claimPolicy(context.Background(), policy, remote(description))).To(Equal(want))
+			if !want {
+				condition := meta.FindStatusCondition(policy.Status.Conditions, batchv1.ConditionSynced)
+				Expect(condition.Reason).To(Equal("NotOwned"))
+			}
+		},
+		Entry("owned by the object", "", "logs [managed-by osindexpolicy default/logs]", nil, true),
+		Entry("the version the object synced", "", "logs",
+			&batchv1.SyncedPolicy{PolicyID: "logs", SeqNo: 5, PrimaryTerm: 1}, true),
+		Entry("synced by the object under another policy_id", "", "logs",
+			&batchv1.SyncedPolicy{PolicyID: "metrics", SeqNo: 5, PrimaryTerm: 1}, false),
+		Entry("changed since the object synced it", "", "logs",
+			&batchv1.SyncedPolicy{PolicyID: "logs", SeqNo: 4, PrimaryTerm: 1}, false),
+		Entry("synced before versions were recorded", "", "logs", nil, false),
+		Entry("made by hand, Fail", "", "logs", nil, false),
+		Entry("made by hand, Adopt", batchv1.AdoptionAdopt, "logs", nil, true),
+		Entry("owned by another object, Adopt", batchv1.AdoptionAdopt, "logs [managed-by osindexpolicy other/logs]", nil, false),
+		Entry("owned by another object, Overwrite", batchv1.AdoptionOverwrite, "logs [managed-by osindexpolicy other/logs]", nil, true),
+	)
+})
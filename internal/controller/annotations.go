@@ -0,0 +1,79 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	batchv1 "github.com/a8uhnf/opensearch-ism-crd/api/v1"
+)
+
+const (
+	eventPaused   = "Paused"
+	eventResynced = "Resynced"
+)
+
+// isPaused reports whether the paused annotation stops the reconciliation of the policy.
+func isPaused(policy batchv1.IndexPolicyObject) bool {
+	return policy.GetAnnotations()[batchv1.AnnotationPaused] == "true"
+}
+
+// pause records in status that the policy is paused, without calling
+// OpenSearch. Removing the annotation triggers the next reconcile.
+func (r *OSIndexPolicyReconciler) pause(ctx context.Context, policy, original batchv1.IndexPolicyObject) (ctrl.Result, error) {
+	logr := logf.FromContext(ctx)
+	if meta.IsStatusConditionTrue(policy.GetStatus().Conditions, batchv1.ConditionPaused) {
+		return ctrl.Result{}, nil
+	}
+	logr.Info("OSIndexPolicy paused, skipping reconciliation", "name", policy.GetName())
+	meta.SetStatusCondition(&policy.GetStatus().Conditions, metav1.Condition{
+		Type:               batchv1.ConditionPaused,
+		Status:             metav1.ConditionTrue,
+		Reason:             "Annotated",
+		Message:            "Reconciliation paused by the " + batchv1.AnnotationPaused + " annotation",
+		ObservedGeneration: policy.GetGeneration(),
+	})
+	r.Recorder.Event(policy, corev1.EventTypeNormal, eventPaused, "Reconciliation paused, OpenSearch is left untouched")
+	if err := r.patchStatus(ctx, policy, original); err != nil {
+		logr.Error(err, "Failed to patch OSIndexPolicy status")
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, nil
+}
+
+// resyncRequested reports whether the resync-at annotation changed since the
+// last resync. The refresh of the throttled explain and attach checks is
+// forced right away, the policy write by syncPolicy.
+func resyncRequested(policy batchv1.IndexPolicyObject) bool {
+	resyncAt := policy.GetAnnotations()[batchv1.AnnotationResyncAt]
+	if resyncAt == "" || resyncAt == policy.GetStatus().LastResyncAt {
+		return false
+	}
+	if policy.GetStatus().ManagedIndices != nil {
+		policy.GetStatus().ManagedIndices.LastExplainTime = metav1.Time{}
+	}
+	if policy.GetStatus().AttachExisting != nil {
+		policy.GetStatus().AttachExisting.LastCheckTime = metav1.Time{}
+	}
+	return true
+}
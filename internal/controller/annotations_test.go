@@ -0,0 +1,99 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	batchv1 "github.com/a8uhnf/opensearch-ism-crd/api/v1"
+	"github.com/a8uhnf/opensearch-ism-crd/internal/pkg/opensearch"
+)
+
+// updateRecorder is an OpenSearch client that records the policy updates.
+type updateRecorder struct {
+	opensearch.OpenSearch
+	updates int
+}
+
+func (u *updateRecorder) UpdateIndexPolicy(_ context.Context, id string, seqNo, primaryTerm int64, _ *batchv1.OpensearchIndexPolicy) (*opensearch.IndexPolicy, error) {
+	u.updates++
+	return &opensearch.IndexPolicy{ID: id, SeqNo: seqNo + 1, PrimaryTerm: primaryTerm}, nil
+}
+
+var _ = Describe("Reconciliation annotations", func() {
+	var policy *batchv1.OSIndexPolicy
+
+	BeforeEach(func() {
+		policy = &batchv1.OSIndexPolicy{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "logs", Annotations: map[string]string{}},
+			Spec: batchv1.OSIndexPolicySpec{
+				PolicyID: "logs",
+				Policy:   batchv1.OpensearchIndexPolicy{Description: "logs"},
+			},
+		}
+	})
+
+	It("skips paused policies without calling OpenSearch", func() {
+		policy.Annotations[batchv1.AnnotationPaused] = "true"
+		scheme := runtime.NewScheme()
+		Expect(batchv1.AddToScheme(scheme)).To(Succeed())
+		reconciler := &OSIndexPolicyReconciler{
+			Client:   fake.NewClientBuilder().WithScheme(scheme).WithObjects(policy).WithStatusSubresource(policy).Build(),
+			Recorder: record.NewFakeRecorder(10),
+		}
+
+		_, err := reconciler.Reconcile(context.Background(), reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "logs"}})
+		Expect(err).NotTo(HaveOccurred())
+
+		paused := &batchv1.OSIndexPolicy{}
+		Expect(reconciler.Get(context.Background(), types.NamespacedName{Namespace: "default", Name: "logs"}, paused)).To(Succeed())
+		Expect(meta.IsStatusConditionTrue(paused.Status.Conditions, batchv1.ConditionPaused)).To(BeTrue())
+	})
+
+	It("requests a resync once per annotation value", func() {
+		Expect(resyncRequested(policy)).To(BeFalse())
+		policy.Annotations[batchv1.AnnotationResyncAt] = "2026-10-19T10:00:00Z"
+		Expect(resyncRequested(policy)).To(BeTrue())
+		policy.Status.LastResyncAt = "2026-10-19T10:00:00Z"
+		Expect(resyncRequested(policy)).To(BeFalse())
+	})
+
+	It("rewrites an unchanged policy when forced", func() {
+		raw, err := json.Marshal(opensearch.WithOwner(&policy.Spec.Policy, "default/logs"))
+		Expect(err).NotTo(HaveOccurred())
+		remote := &opensearch.IndexPolicy{ID: "logs", SeqNo: 7, Policy: raw}
+		client := &updateRecorder{}
+		reconciler := &OSIndexPolicyReconciler{Recorder: record.NewFakeRecorder(10)}
+
+		Expect(reconciler.syncPolicy(context.Background(), client, policy, remote, false)).To(Succeed())
+		Expect(client.updates).To(BeZero())
+		Expect(reconciler.syncPolicy(context.Background(), client, policy, remote, true)).To(Succeed())
+		Expect(client.updates).To(Equal(1))
+		Expect(meta.FindStatusCondition(policy.Status.Conditions, batchv1.ConditionSynced).Reason).To(Equal(eventResynced))
+	})
+})
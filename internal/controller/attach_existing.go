@@ -0,0 +1,127 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"path"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	batchv1 "github.com/a8uhnf/opensearch-ism-crd/api/v1"
+	"github.com/a8uhnf/opensearch-ism-crd/internal/pkg/opensearch"
+)
+
+const (
+	eventIndicesAttached = "IndicesAttached"
+	eventAttachFailed    = "AttachFailed"
+)
+
+// attachExistingIndices lists the unmanaged indices matching spec.attach_existing,
+// at most once per explain interval, and reports them in status. In Attach mode
+// it attaches the policy to them, unless in plan mode. Failures are reported but
+// do not fail the sync.
+func (r *OSIndexPolicyReconciler) attachExistingIndices(ctx context.Context, opensearchClient opensearch.OpenSearch, policy batchv1.IndexPolicyObject) {
+	spec := policy.GetSpec().AttachExisting
+	if spec == nil {
+		policy.GetStatus().AttachExisting = nil
+		return
+	}
+	mode := spec.Mode
+	if mode == "" || r.planning(policy) {
+		mode = batchv1.AttachModePreview
+	}
+	interval := r.ExplainInterval
+	if interval <= 0 {
+		interval = defaultExplainInterval
+	}
+	// Switching from Preview to Attach acts right away.
+	if last := policy.GetStatus().AttachExisting; last != nil && last.Mode == mode && time.Since(last.LastCheckTime.Time) < interval {
+		return
+	}
+	logr := logf.FromContext(ctx)
+
+	unmanaged, err := opensearchClient.UnmanagedIndices(ctx, spec.IndexPatterns)
+	if err != nil {
+		logr.Error(err, "Failed to list unmanaged indices", "policyName", policy.GetName())
+		r.Recorder.Eventf(policy, corev1.EventTypeWarning, eventAttachFailed, "Failed to list unmanaged indices: %v", err)
+		return
+	}
+	matching := excludeIndices(unmanaged, spec.Exclude)
+	status := &batchv1.AttachExistingStatus{
+		Mode:          mode,
+		MatchingCount: len(matching),
+		LastCheckTime: metav1.Now(),
+	}
+	status.MatchingIndices = matching[:min(len(matching), maxFailedIndices)]
+	// As for the explain sweep, the time only moves when the outcome changes.
+	if last := policy.GetStatus().AttachExisting; last != nil && !last.LastCheckTime.IsZero() {
+		unchanged := *status
+		unchanged.LastCheckTime = last.LastCheckTime
+		if equality.Semantic.DeepEqual(last, &unchanged) {
+			status.LastCheckTime = last.LastCheckTime
+		}
+	}
+	policy.GetStatus().AttachExisting = status
+	if mode != batchv1.AttachModeAttach || len(matching) == 0 {
+		return
+	}
+
+	result, err := opensearchClient.AddPolicy(ctx, matching, policy.GetSpec().PolicyID)
+	if err != nil {
+		logr.Error(err, "Failed to attach index policy to existing indices", "policyName", policy.GetName())
+		r.Recorder.Eventf(policy, corev1.EventTypeWarning, eventAttachFailed, "Failed to attach index policy %s: %v", policy.GetSpec().PolicyID, err)
+		return
+	}
+	status.AttachedCount = result.UpdatedIndices
+	failures := result.FailedIndices
+	sort.Slice(failures, func(i, j int) bool { return failures[i].Index < failures[j].Index })
+	for _, failure := range failures[:min(len(failures), maxFailedIndices)] {
+		status.FailedIndices = append(status.FailedIndices, batchv1.FailedIndex{Index: failure.Index, Message: failure.Reason})
+	}
+	if len(failures) > 0 {
+		r.Recorder.Eventf(policy, corev1.EventTypeWarning, eventAttachFailed,
+			"ISM add rejected %d of %d existing indices", len(failures), len(matching))
+	}
+	if result.UpdatedIndices > 0 {
+		r.Recorder.Eventf(policy, corev1.EventTypeNormal, eventIndicesAttached,
+			"Attached index policy %s to %d existing indices", policy.GetSpec().PolicyID, result.UpdatedIndices)
+	}
+}
+
+// excludeIndices drops the indices matching one of the exclusion patterns.
+func excludeIndices(indices []string, exclude []string) []string {
+	kept := make([]string, 0, len(indices))
+	for _, index := range indices {
+		excluded := false
+		for _, pattern := range exclude {
+			if matched, _ := path.Match(pattern, index); matched {
+				excluded = true
+				break
+			}
+		}
+		if !excluded {
+			kept = append(kept, index)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	batchv1 "github.com/a8uhnf/opensearch-ism-crd/api/v1"
+	"github.com/a8uhnf/opensearch-ism-crd/internal/pkg/opensearch"
+)
+
+// attachRecorder is an OpenSearch client that records the attached indices.
+type attachRecorder struct {
+	opensearch.OpenSearch
+	unmanaged []string
+	attached  []string
+}
+
+func (a *attachRecorder) UnmanagedIndices(_ context.Context, _ []string) ([]string, error) {
+	return a.unmanaged, nil
+}
+
+func (a *attachRecorder) AddPolicy(_ context.Context, indices []string, _ string) (*opensearch.ChangePolicyResult, error) {
+	a.attached = append(a.attached, indices...)
+	return &opensearch.ChangePolicyResult{UpdatedIndices: len(indices)}, nil
+}
+
+var _ = Describe("Attaching existing indices", func() {
+	var (
+		reconciler *OSIndexPolicyReconciler
+		client     *attachRecorder
+		policy     *batchv1.OSIndexPolicy
+	)
+
+	BeforeEach(func() {
+		reconciler = &OSIndexPolicyReconciler{Recorder: record.NewFakeRecorder(10)}
+		client = &attachRecorder{unmanaged: []string{"logs-000001", "logs-000002", "logs-restored"}}
+		policy = &batchv1.OSIndexPolicy{
+			Spec: batchv1.OSIndexPolicySpec{
+				PolicyID: "logs",
+				AttachExisting: &batchv1.AttachExisting{
+					IndexPatterns: []string{"logs-*"},
+					Exclude:       []string{"*-restored"},
+				},
+			},
+		}
+	})
+
+	It("only previews the matching indices by default", func() {
+		reconciler.attachExistingIndices(context.Background(), client, policy)
+
+		Expect(client.attached).To(BeEmpty())
+		Expect(policy.Status.AttachExisting.Mode).To(Equal(batchv1.AttachModePreview))
+		Expect(policy.Status.AttachExisting.MatchingCount).To(Equal(2))
+		Expect(policy.Status.AttachExisting.MatchingIndices).To(Equal([]string{"logs-000001", "logs-000002"}))
+	})
+
+	It("attaches the matching indices in Attach mode, right after a preview", func() {
+		reconciler.attachExistingIndices(context.Background(), client, policy)
+		policy.Spec.AttachExisting.Mode = batchv1.AttachModeAttach
+		reconciler.attachExistingIndices(context.Background(), client, policy)
+
+		Expect(client.attached).To(Equal([]string{"logs-000001", "logs-000002"}))
+		Expect(policy.Status.AttachExisting.AttachedCount).To(Equal(2))
+	})
+	It("keeps the check time while the matching indices do not change", func() {
+		reconciler.ExplainInterval = time.Nanosecond
+		reconciler.attachExistingIndices(context.Background(), client, policy)
+		checked := metav1.NewTime(time.Now().Add(-time.Hour))
+		policy.Status.AttachExisting.LastCheckTime = checked
+
+		reconciler.attachExistingIndices(context.Background(), client, policy)
+		Expect(policy.Status.AttachExisting.LastCheckTime).To(Equal(checked))
+
+		client.unmanaged = append(client.unmanaged, "logs-000003")
+		reconciler.attachExistingIndices(context.Background(), client, policy)
+		Expect(policy.Status.AttachExisting.LastCheckTime.Time).To(BeTemporally(">", checked.Time))
+	})
+})
@@ -0,0 +1,125 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	batchv1 "github.com/a8uhnf/opensearch-ism-crd/api/v1"
+	"github.com/a8uhnf/opensearch-ism-crd/internal/pkg/opensearch"
+)
+
+const (
+	// defaultRetryAttempts and defaultRetryBackoff apply when spec.auto_retry leaves them unset.
+	defaultRetryAttempts = 3
+	defaultRetryBackoff  = 5 * time.Minute
+
+	eventIndexRetried     = "IndexRetried"
+	eventIndexRetryFailed = "IndexRetryFailed"
+	eventRetriesExhausted = "RetriesExhausted"
+)
+
+// retryFailedIndices sends the ISM retry of the failed managed indices that
+// spec.auto_retry selects, and records the attempts in status. Attempts are
+// kept while an index stays in the state it failed in. Nothing is retried in
+// plan mode.
+func (r *OSIndexPolicyReconciler) retryFailedIndices(ctx context.Context, opensearchClient opensearch.OpenSearch, policy batchv1.IndexPolicyObject, indices []opensearch.ManagedIndex) {
+	if r.planning(policy) {
+		return
+	}
+	autoRetry := policy.GetSpec().AutoRetry
+	if autoRetry == nil {
+		policy.GetStatus().RetryAttempts = nil
+		return
+	}
+	maxAttempts := autoRetry.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = defaultRetryAttempts
+	}
+	backoff := defaultRetryBackoff
+	if autoRetry.Backoff != nil {
+		backoff = autoRetry.Backoff.Duration
+	}
+
+	previous := map[string]batchv1.IndexRetryStatus{}
+	for _, attempt := range policy.GetStatus().RetryAttempts {
+		previous[attempt.Index] = attempt
+	}
+	var attempts []batchv1.IndexRetryStatus
+	for _, index := range indices {
+		attempt, ok := previous[index.Index]
+		if !ok || attempt.State != index.State {
+			attempt = batchv1.IndexRetryStatus{Index: index.Index, State: index.State}
+		}
+		if index.Failed && (len(autoRetry.States) == 0 || slices.Contains(autoRetry.States, index.State)) {
+			r.retryIndex(ctx, opensearchClient, policy, &attempt, maxAttempts, backoff)
+		}
+		if attempt.Attempts > 0 || attempt.LastError != "" {
+			attempts = append(attempts, attempt)
+		}
+	}
+	policy.GetStatus().RetryAttempts = attempts
+}
+
+// retryIndex retries the index once its backoff has elapsed, unless it ran out of attempts.
+func (r *OSIndexPolicyReconciler) retryIndex(ctx context.Context, opensearchClient opensearch.OpenSearch, policy batchv1.IndexPolicyObject, attempt *batchv1.IndexRetryStatus, maxAttempts int, backoff time.Duration) {
+	if attempt.Attempts >= maxAttempts {
+		return
+	}
+	if attempt.Attempts > 0 && time.Since(attempt.LastAttemptTime.Time) < retryBackoff(backoff, attempt.Attempts) {
+		return
+	}
+
+	logr := logf.FromContext(ctx)
+	// Failed attempts count too, so that an unreachable cluster backs off and gives up as well.
+	attempt.Attempts++
+	attempt.LastAttemptTime = metav1.Now()
+	attempt.LastError = ""
+	if err := opensearchClient.RetryManagedIndex(ctx, attempt.Index); err != nil {
+		logr.Error(err, "Failed to retry managed index", "index", attempt.Index, "attempt", attempt.Attempts)
+		attempt.LastError = err.Error()
+		r.Recorder.Eventf(policy, corev1.EventTypeWarning, eventIndexRetryFailed,
+			"Failed to retry index %s (attempt %d/%d): %v", attempt.Index, attempt.Attempts, maxAttempts, err)
+	} else {
+		logr.Info("Retried failed managed index", "index", attempt.Index, "attempt", attempt.Attempts)
+	}
+	if attempt.Attempts >= maxAttempts {
+		r.Recorder.Eventf(policy, corev1.EventTypeWarning, eventRetriesExhausted,
+			"Retried index %s in state %s (attempt %d/%d), no further automatic retries", attempt.Index, attempt.State, attempt.Attempts, maxAttempts)
+		return
+	}
+	if attempt.LastError == "" {
+		r.Recorder.Eventf(policy, corev1.EventTypeNormal, eventIndexRetried,
+			"Retried index %s in state %s (attempt %d/%d)", attempt.Index, attempt.State, attempt.Attempts, maxAttempts)
+	}
+}
+
+// retryBackoff is the wait after the given number of attempts: the base
+// backoff after the first, doubled after every further one.
+func retryBackoff(base time.Duration, attempts int) time.Duration {
+	wait := base
+	for i := 1; i < attempts && wait < 24*time.Hour; i++ {
+		wait *= 2
+	}
+	return wait
+}
@@ -0,0 +1,132 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	batchv1 "github.com/a8uhnf/opensearch-ism-crd/api/v1"
+	"github.com/a8uhnf/opensearch-ism-crd/internal/pkg/opensearch"
+)
+
+// retryRecorder is an OpenSearch client that records the retried indices.
+type retryRecorder struct {
+	opensearch.OpenSearch
+	retried []string
+	err     error
+}
+
+func (r *retryRecorder) RetryManagedIndex(_ context.Context, index string) error {
+	r.retried = append(r.retried, index)
+	return r.err
+}
+
+var _ = Describe("Automatic retry of failed indices", func() {
+	var (
+		reconciler *OSIndexPolicyReconciler
+		client     *retryRecorder
+		policy     *batchv1.OSIndexPolicy
+		indices    []opensearch.ManagedIndex
+	)
+
+	BeforeEach(func() {
+		reconciler = &OSIndexPolicyReconciler{Recorder: record.NewFakeRecorder(10)}
+		client = &retryRecorder{}
+		policy = &batchv1.OSIndexPolicy{
+			Spec: batchv1.OSIndexPolicySpec{
+				AutoRetry: &batchv1.AutoRetry{
+					MaxAttempts: 2,
+					Backoff:     &metav1.Duration{Duration: time.Hour},
+					States:      []string{"hot"},
+				},
+			},
+		}
+		indices = []opensearch.ManagedIndex{
+			{Index: "logs-000001", State: "hot", Failed: true},
+			{Index: "logs-000002", State: "warm", Failed: true},
+			{Index: "logs-000003", State: "hot"},
+		}
+	})
+
+	It("retries the failed indices of the selected states", func() {
+		reconciler.retryFailedIndices(context.Background(), client, policy, indices)
+
+		Expect(client.retried).To(Equal([]string{"logs-000001"}))
+		Expect(policy.Status.RetryAttempts).To(HaveLen(1))
+		Expect(policy.Status.RetryAttempts[0].Index).To(Equal("logs-000001"))
+		Expect(policy.Status.RetryAttempts[0].Attempts).To(Equal(1))
+	})
+
+	It("waits for the backoff and stops after the last attempt", func() {
+		reconciler.retryFailedIndices(context.Background(), client, policy, indices)
+		reconciler.retryFailedIndices(context.Background(), client, policy, indices)
+		Expect(client.retried).To(HaveLen(1))
+
+		policy.Status.RetryAttempts[0].LastAttemptTime = metav1.NewTime(time.Now().Add(-2 * time.Hour))
+		reconciler.retryFailedIndices(context.Background(), client, policy, indices)
+		Expect(client.retried).To(HaveLen(2))
+
+		policy.Status.RetryAttempts[0].LastAttemptTime = metav1.NewTime(time.Now().Add(-48 * time.Hour))
+		reconciler.retryFailedIndices(context.Background(), client, policy, indices)
+		Expect(client.retried).To(HaveLen(2))
+	})
+
+	It("backs off and gives up when the retry fails", func() {
+		client.err = errors.New("connection refused")
+		reconciler.retryFailedIndices(context.Background(), client, policy, indices)
+		Expect(policy.Status.RetryAttempts).To(HaveLen(1))
+		Expect(policy.Status.RetryAttempts[0].Attempts).To(Equal(1))
+		Expect(policy.Status.RetryAttempts[0].LastError).To(Equal("connection refused"))
+
+		reconciler.retryFailedIndices(context.Background(), client, policy, indices)
+		Expect(client.retried).To(HaveLen(1))
+
+		policy.Status.RetryAttempts[0].LastAttemptTime = metav1.NewTime(time.Now().Add(-2 * time.Hour))
+		reconciler.retryFailedIndices(context.Background(), client, policy, indices)
+		Expect(client.retried).To(HaveLen(2))
+		Expect(policy.Status.RetryAttempts[0].Attempts).To(Equal(2))
+
+		policy.Status.RetryAttempts[0].LastAttemptTime = metav1.NewTime(time.Now().Add(-48 * time.Hour))
+		reconciler.retryFailedIndices(context.Background(), client, policy, indices)
+		Expect(client.retried).To(HaveLen(2))
+	})
+
+	It("starts over once the index moved to another state", func() {
+		policy.Status.RetryAttempts = []batchv1.IndexRetryStatus{{Index: "logs-000001", State: "rollover", Attempts: 2}}
+		reconciler.retryFailedIndices(context.Background(), client, policy, indices)
+
+		Expect(client.retried).To(Equal([]string{"logs-000001"}))
+		Expect(policy.Status.RetryAttempts[0].State).To(Equal("hot"))
+		Expect(policy.Status.RetryAttempts[0].Attempts).To(Equal(1))
+	})
+
+	It("clears the attempts when disabled", func() {
+		policy.Status.RetryAttempts = []batchv1.IndexRetryStatus{{Index: "logs-000001", State: "hot", Attempts: 1}}
+		policy.Spec.AutoRetry = nil
+		reconciler.retryFailedIndices(context.Background(), client, policy, indices)
+
+		Expect(client.retried).To(BeEmpty())
+		Expect(policy.Status.RetryAttempts).To(BeNil())
+	})
+})
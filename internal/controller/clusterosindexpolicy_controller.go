@@ -0,0 +1,117 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	batchv1 "github.com/a8uhnf/opensearch-ism-crd/api/v1"
+	"github.com/a8uhnf/opensearch-ism-crd/internal/metrics"
+	"github.com/a8uhnf/opensearch-ism-crd/internal/pkg/opensearch"
+)
+
+// ClusterOSIndexPolicyReconciler reconciles a ClusterOSIndexPolicy object with
+// the logic and settings of the OSIndexPolicyReconciler it embeds.
+type ClusterOSIndexPolicyReconciler struct {
+	*OSIndexPolicyReconciler
+}
+
+// +kubebuilder:rbac:groups=batch.a8uhnf.com,resources=clusterosindexpolicies,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=batch.a8uhnf.com,resources=clusterosindexpolicies/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=batch.a8uhnf.com,resources=clusterosindexpolicies/finalizers,verbs=update
+
+// Reconcile syncs the ISM policy of a ClusterOSIndexPolicy to OpenSearch.
+func (r *ClusterOSIndexPolicyReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logr := logf.FromContext(ctx)
+
+	logr.Info("Reconciling ClusterOSIndexPolicy", "name", req.Name)
+
+	policy := &batchv1.ClusterOSIndexPolicy{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "ClusterOSIndexPolicy",
+			APIVersion: batchv1.GroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{Name: req.Name},
+	}
+	if err := r.Get(ctx, req.NamespacedName, policy); err != nil {
+		if client.IgnoreNotFound(err) != nil {
+			logr.Error(err, "Failed to get ClusterOSIndexPolicy")
+			return ctrl.Result{}, err
+		}
+		// Resource not found, drop its cached client and metrics and don't requeue
+		r.releaseClients(ctx, req.Name)
+		metrics.Forget(req.Name)
+		return ctrl.Result{}, nil
+	}
+	return r.reconcilePolicy(ctx, policy)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ClusterOSIndexPolicyReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if r.Clients == nil {
+		r.Clients = opensearch.NewClientCache()
+	}
+	if r.Recorder == nil {
+		r.Recorder = mgr.GetEventRecorderFor("clusterosindexpolicy-controller")
+	}
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &batchv1.ClusterOSIndexPolicy{}, connectionSecretsIndex,
+		func(obj client.Object) []string {
+			return connectionSecretNames(obj.(*batchv1.ClusterOSIndexPolicy).Spec.OpensearhConnection)
+		}); err != nil {
+		return err
+	}
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &batchv1.ClusterOSIndexPolicy{}, templateRefIndex,
+		func(obj client.Object) []string {
+			return templateRefName(&obj.(*batchv1.ClusterOSIndexPolicy).Spec)
+		}); err != nil {
+		return err
+	}
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &batchv1.ClusterOSIndexPolicy{}, baseIndex,
+		func(obj client.Object) []string {
+			return baseName(&obj.(*batchv1.ClusterOSIndexPolicy).Spec)
+		}); err != nil {
+		return err
+	}
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &batchv1.ClusterOSIndexPolicy{}, policyConfigMapIndex,
+		func(obj client.Object) []string {
+			return policyConfigMapName(&obj.(*batchv1.ClusterOSIndexPolicy).Spec)
+		}); err != nil {
+		return err
+	}
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&batchv1.ClusterOSIndexPolicy{}, builder.WithPredicates(
+			predicate.Or(predicate.GenerationChangedPredicate{}, predicate.AnnotationChangedPredicate{}))).
+		Watches(&corev1.Secret{}, handler.EnqueueRequestsFromMapFunc(r.clusterPoliciesForSecret)).
+		Watches(&corev1.ConfigMap{}, handler.EnqueueRequestsFromMapFunc(r.clusterPoliciesForConfigMap)).
+		Watches(&batchv1.OpenSearchCluster{}, handler.EnqueueRequestsFromMapFunc(r.clusterPoliciesForCluster)).
+		Watches(&batchv1.OSIndexPolicyTemplate{}, handler.EnqueueRequestsFromMapFunc(r.clusterPoliciesForTemplate)).
+		Watches(&batchv1.ClusterOSIndexPolicy{}, handler.EnqueueRequestsFromMapFunc(r.clusterPoliciesForBase),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		WithOptions(controller.Options{RateLimiter: r.rateLimiter()}).
+		Named("clusterosindexpolicy").
+		Complete(r)
+}
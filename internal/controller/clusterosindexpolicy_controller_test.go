@@ -0,0 +1,78 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	batchv1 "github.com/a8uhnf/opensearch-ism-crd/api/v1"
+)
+
+var _ = Describe("ClusterOSIndexPolicy Controller", func() {
+	var policy *batchv1.ClusterOSIndexPolicy
+
+	BeforeEach(func() {
+		policy = &batchv1.ClusterOSIndexPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "audit", Annotations: map[string]string{}},
+			Spec: batchv1.OSIndexPolicySpec{
+				PolicyID: "audit",
+				Policy:   batchv1.OpensearchIndexPolicy{Description: "audit"},
+			},
+		}
+	})
+
+	It("reconciles with the logic of OSIndexPolicies", func() {
+		policy.Annotations[batchv1.AnnotationPaused] = "true"
+		scheme := runtime.NewScheme()
+		Expect(batchv1.AddToScheme(scheme)).To(Succeed())
+		reconciler := &ClusterOSIndexPolicyReconciler{OSIndexPolicyReconciler: &OSIndexPolicyReconciler{
+			Client:   fake.NewClientBuilder().WithScheme(scheme).WithObjects(policy).WithStatusSubresource(policy).Build(),
+			Recorder: record.NewFakeRecorder(10),
+		}}
+
+		_, err := reconciler.Reconcile(context.Background(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "audit"}})
+		Expect(err).NotTo(HaveOccurred())
+
+		paused := &batchv1.ClusterOSIndexPolicy{}
+		Expect(reconciler.Get(context.Background(), types.NamespacedName{Name: "audit"}, paused)).To(Succeed())
+		Expect(meta.IsStatusConditionTrue(paused.Status.Conditions, batchv1.ConditionPaused)).To(BeTrue())
+	})
+
+	It("names cluster-scoped policies without a namespace", func() {
+		Expect(policyKey(policy)).To(Equal("audit"))
+		Expect(policyKey(&batchv1.OSIndexPolicy{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "logs"}})).To(Equal("default/logs"))
+	})
+
+	It("reads the Secrets of cluster-scoped policies from the configured namespace", func() {
+		reconciler := &OSIndexPolicyReconciler{ClusterSecretNamespace: "os-index-policy"}
+		Expect(reconciler.referenceNamespace(policy)).To(Equal("os-index-policy"))
+		Expect(reconciler.referenceNamespace(&batchv1.OSIndexPolicy{ObjectMeta: metav1.ObjectMeta{Namespace: "team-a"}})).To(Equal("team-a"))
+
+		_, err := (&OSIndexPolicyReconciler{}).getSecret(context.Background(), "", "opensearch-credentials")
+		Expect(err).To(MatchError(ContainSubstring("no namespace configured")))
+	})
+})
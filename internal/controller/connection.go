@@ -0,0 +1,332 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	batchv1 "github.com/a8uhnf/opensearch-ism-crd/api/v1"
+	"github.com/a8uhnf/opensearch-ism-crd/internal/pkg/opensearch"
+)
+
+const (
+	// connectionSecretsIndex indexes OSIndexPolicies and ClusterOSIndexPolicies by
+	// the Secrets their connection reads.
+	connectionSecretsIndex = ".spec.opensearch_connection.secrets"
+
+	usernameKey = "username"
+	passwordKey = "password"
+	caCertKey   = "ca.crt"
+
+	awsAccessKeyIDKey     = "aws_access_key_id"
+	awsSecretAccessKeyKey = "aws_secret_access_key"
+	awsSessionTokenKey    = "aws_session_token"
+)
+
+// connectionSecretNames returns the names of the Secrets referenced by the connection.
+func connectionSecretNames(conn batchv1.OpensearhConnection) []string {
+	var names []string
+	if conn.CredentialsSecretRef != nil && conn.CredentialsSecretRef.Name != "" {
+		names = append(names, conn.CredentialsSecretRef.Name)
+	}
+	if conn.TLS != nil && conn.TLS.CASecretRef != nil && conn.TLS.CASecretRef.Name != "" {
+		names = append(names, conn.TLS.CASecretRef.Name)
+	}
+	if auth := conn.Auth; auth != nil {
+		switch {
+		case auth.Basic != nil:
+			names = append(names, auth.Basic.CredentialsSecretRef.Name)
+		case auth.BearerTokenSecretRef != nil:
+			names = append(names, auth.BearerTokenSecretRef.Name)
+		case auth.APIKeySecretRef != nil:
+			names = append(names, auth.APIKeySecretRef.Name)
+		case auth.ClientCertificate != nil:
+			names = append(names, auth.ClientCertificate.SecretRef.Name)
+		case auth.AWS != nil && auth.AWS.CredentialsSecretRef != nil:
+			names = append(names, auth.AWS.CredentialsSecretRef.Name)
+		}
+	}
+	return names
+}
+
+// secretSource is the ClientCache source name of a Secret.
+func secretSource(namespace, name string) string {
+	return "secret/" + namespace + "/" + name
+}
+
+// openSearchClient returns the cached OpenSearch client for the policy's connection.
+func (r *OSIndexPolicyReconciler) openSearchClient(ctx context.Context, policy batchv1.IndexPolicyObject) (opensearch.OpenSearch, error) {
+	conn := policy.GetSpec().OpensearhConnection
+	config := opensearch.OpenSearchConfig{
+		URL:       conn.URL,
+		Addresses: conn.URLs,
+		Username:  conn.Username,
+		Password:  conn.Password,
+	}
+	// identity collects everything that makes two connections differ. Secrets
+	// contribute their resourceVersion rather than their content.
+	identity := []string{
+		"url=" + conn.URL,
+		"urls=" + strings.Join(conn.URLs, ","),
+		"username=" + conn.Username,
+		"password=" + hashString(conn.Password),
+	}
+	if sniffing := conn.Sniffing; sniffing != nil {
+		config.DiscoverNodesOnStart = sniffing.OnStart
+		if sniffing.Interval != nil {
+			config.DiscoverNodesInterval = sniffing.Interval.Duration
+		}
+		identity = append(identity, fmt.Sprintf("sniffing=%t/%s", config.DiscoverNodesOnStart, config.DiscoverNodesInterval))
+	}
+	var sources []string
+	namespace := r.referenceNamespace(policy)
+
+	if ref := conn.CredentialsSecretRef; ref != nil {
+		secret, err := r.getSecret(ctx, namespace, ref.Name)
+		if err != nil {
+			return nil, err
+		}
+		config.Username = string(secret.Data[usernameKey])
+		config.Password = string(secret.Data[passwordKey])
+		identity = append(identity, fmt.Sprintf("credentials=%s@%s", ref.Name, secret.ResourceVersion))
+		sources = append(sources, secretSource(secret.Namespace, secret.Name))
+	}
+
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: true, // Set to true for testing purposes, should be false in production
+	}
+	if t := conn.TLS; t != nil {
+		tlsConfig.InsecureSkipVerify = t.InsecureSkipVerify
+		if ref := t.CASecretRef; ref != nil {
+			secret, err := r.getSecret(ctx, namespace, ref.Name)
+			if err != nil {
+				return nil, err
+			}
+			pool := x509.NewCertPool()
+			if !pool.AppendCertsFromPEM(secret.Data[caCertKey]) {
+				return nil, fmt.Errorf("secret %s/%s has no valid PEM certificate under %q", secret.Namespace, secret.Name, caCertKey)
+			}
+			tlsConfig.RootCAs = pool
+			identity = append(identity, fmt.Sprintf("ca=%s@%s", ref.Name, secret.ResourceVersion))
+			sources = append(sources, secretSource(secret.Namespace, secret.Name))
+		}
+	}
+	identity = append(identity, fmt.Sprintf("insecure=%t", tlsConfig.InsecureSkipVerify))
+
+	if auth := conn.Auth; auth != nil {
+		authIdentity, authSources, err := r.authConfig(ctx, namespace, auth, &config)
+		if err != nil {
+			return nil, err
+		}
+		identity = append(identity, authIdentity...)
+		sources = append(sources, authSources...)
+	}
+	config.TLSConfig = &http.Transport{
+		Proxy:           http.ProxyFromEnvironment,
+		TLSClientConfig: tlsConfig,
+	}
+
+	owner := clientOwner(ctx, policy)
+	return r.Clients.Get(ctx, owner, hashString(strings.Join(identity, "|")), sources, config)
+}
+
+// authConfig applies the authentication mode selected by auth to config and
+// returns the identity entries and cache sources it contributes.
+func (r *OSIndexPolicyReconciler) authConfig(ctx context.Context, namespace string, auth *batchv1.OpensearchAuth, config *opensearch.OpenSearchConfig) ([]string, []string, error) {
+	switch {
+	case auth.Basic != nil:
+		ref := auth.Basic.CredentialsSecretRef
+		secret, err := r.getSecret(ctx, namespace, ref.Name)
+		if err != nil {
+			return nil, nil, err
+		}
+		config.Username = string(secret.Data[usernameKey])
+		config.Password = string(secret.Data[passwordKey])
+		return []string{fmt.Sprintf("basic=%s@%s", ref.Name, secret.ResourceVersion)},
+			[]string{secretSource(secret.Namespace, secret.Name)}, nil
+	case auth.BearerTokenSecretRef != nil:
+		token, secret, err := r.getSecretKey(ctx, namespace, auth.BearerTokenSecretRef)
+		if err != nil {
+			return nil, nil, err
+		}
+		config.BearerToken = token
+		return []string{fmt.Sprintf("bearer=%s/%s@%s", secret.Name, auth.BearerTokenSecretRef.Key, secret.ResourceVersion)},
+			[]string{secretSource(secret.Namespace, secret.Name)}, nil
+	case auth.APIKeySecretRef != nil:
+		key, secret, err := r.getSecretKey(ctx, namespace, auth.APIKeySecretRef)
+		if err != nil {
+			return nil, nil, err
+		}
+		config.APIKey = key
+		return []string{fmt.Sprintf("api-key=%s/%s@%s", secret.Name, auth.APIKeySecretRef.Key, secret.ResourceVersion)},
+			[]string{secretSource(secret.Namespace, secret.Name)}, nil
+	case auth.ClientCertificate != nil:
+		ref := auth.ClientCertificate.SecretRef
+		secret, err := r.getSecret(ctx, namespace, ref.Name)
+		if err != nil {
+			return nil, nil, err
+		}
+		cert, err := tls.X509KeyPair(secret.Data[corev1.TLSCertKey], secret.Data[corev1.TLSPrivateKeyKey])
+		if err != nil {
+			return nil, nil, fmt.Errorf("secret %s/%s has no valid client certificate: %w", secret.Namespace, secret.Name, err)
+		}
+		config.ClientCertificate = &cert
+		return []string{fmt.Sprintf("client-certificate=%s@%s", ref.Name, secret.ResourceVersion)},
+			[]string{secretSource(secret.Namespace, secret.Name)}, nil
+	case auth.AWS != nil:
+		awsConfig, identity, sources, err := r.awsConfig(ctx, namespace, auth.AWS)
+		if err != nil {
+			return nil, nil, err
+		}
+		config.AWS = awsConfig
+		return identity, sources, nil
+	}
+	return nil, nil, nil
+}
+
+// awsConfig resolves the SigV4 settings, reading static credentials from the
+// referenced Secret or falling back to the IRSA web identity of the manager.
+func (r *OSIndexPolicyReconciler) awsConfig(ctx context.Context, namespace string, aws *batchv1.AWSAuth) (*opensearch.AWSConfig, []string, []string, error) {
+	service := aws.Service
+	if service == "" {
+		service = opensearch.AWSServiceES
+	}
+	config := &opensearch.AWSConfig{Region: aws.Region, Service: service}
+	identity := []string{"aws-region=" + aws.Region, "aws-service=" + service}
+
+	if ref := aws.CredentialsSecretRef; ref != nil {
+		secret, err := r.getSecret(ctx, namespace, ref.Name)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		config.Credentials = opensearch.StaticAWSCredentials{
+			AccessKeyID:     string(secret.Data[awsAccessKeyIDKey]),
+			SecretAccessKey: string(secret.Data[awsSecretAccessKeyKey]),
+			SessionToken:    string(secret.Data[awsSessionTokenKey]),
+		}
+		identity = append(identity, fmt.Sprintf("aws-credentials=%s@%s", ref.Name, secret.ResourceVersion))
+		return config, identity, []string{secretSource(secret.Namespace, secret.Name)}, nil
+	}
+
+	webIdentity, err := opensearch.NewWebIdentityCredentialsFromEnv(aws.Region, aws.RoleARN)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	config.Credentials = webIdentity
+	identity = append(identity, "aws-role="+webIdentity.RoleARN)
+	return config, identity, nil, nil
+}
+
+// referenceNamespace returns the namespace the policy's references are read
+// from: its Secrets, OSIndexPolicyTemplates, policy_from ConfigMaps and target
+// OpenSearchClusters. An OSIndexPolicy reads them from its own namespace, a
+// ClusterOSIndexPolicy from ClusterSecretNamespace.
+func (r *OSIndexPolicyReconciler) referenceNamespace(policy batchv1.IndexPolicyObject) string {
+	if namespace := policy.GetNamespace(); namespace != "" {
+		return namespace
+	}
+	return r.ClusterSecretNamespace
+}
+
+func (r *OSIndexPolicyReconciler) getSecret(ctx context.Context, namespace, name string) (*corev1.Secret, error) {
+	if namespace == "" {
+		return nil, fmt.Errorf("failed to get secret %s: no namespace configured for the Secrets of cluster-scoped policies", name)
+	}
+	secret := &corev1.Secret{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, secret); err != nil {
+		return nil, fmt.Errorf("failed to get secret %s/%s: %w", namespace, name, err)
+	}
+	return secret, nil
+}
+
+// getSecretKey returns the value of the selected Secret key.
+func (r *OSIndexPolicyReconciler) getSecretKey(ctx context.Context, namespace string, selector *corev1.SecretKeySelector) (string, *corev1.Secret, error) {
+	secret, err := r.getSecret(ctx, namespace, selector.Name)
+	if err != nil {
+		return "", nil, err
+	}
+	value, ok := secret.Data[selector.Key]
+	if !ok {
+		return "", nil, fmt.Errorf("secret %s/%s has no key %q", namespace, selector.Name, selector.Key)
+	}
+	return strings.TrimSpace(string(value)), secret, nil
+}
+
+// policiesForSecret evicts cached clients built from the Secret and requeues
+// every OSIndexPolicy whose connection, or target OpenSearchCluster, references it.
+func (r *OSIndexPolicyReconciler) policiesForSecret(ctx context.Context, obj client.Object) []reconcile.Request {
+	r.Clients.EvictSource(ctx, secretSource(obj.GetNamespace(), obj.GetName()))
+
+	policies := &batchv1.OSIndexPolicyList{}
+	if err := r.List(ctx, policies,
+		client.InNamespace(obj.GetNamespace()),
+		client.MatchingFields{connectionSecretsIndex: obj.GetName()},
+	); err != nil {
+		return nil
+	}
+	requests := make([]reconcile.Request, 0, len(policies.Items))
+	for _, p := range policies.Items {
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Namespace: p.Namespace, Name: p.Name},
+		})
+	}
+	for _, cluster := range r.clustersForSecret(ctx, obj) {
+		requests = append(requests, r.policiesForCluster(ctx, &cluster)...)
+	}
+	return requests
+}
+
+// clusterPoliciesForSecret requeues every ClusterOSIndexPolicy whose connection,
+// or target OpenSearchCluster, references the Secret, when it lives in ClusterSecretNamespace.
+func (r *OSIndexPolicyReconciler) clusterPoliciesForSecret(ctx context.Context, obj client.Object) []reconcile.Request {
+	if r.ClusterSecretNamespace == "" || obj.GetNamespace() != r.ClusterSecretNamespace {
+		return nil
+	}
+	r.Clients.EvictSource(ctx, secretSource(obj.GetNamespace(), obj.GetName()))
+
+	policies := &batchv1.ClusterOSIndexPolicyList{}
+	if err := r.List(ctx, policies, client.MatchingFields{connectionSecretsIndex: obj.GetName()}); err != nil {
+		return nil
+	}
+	requests := make([]reconcile.Request, 0, len(policies.Items))
+	for _, p := range policies.Items {
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Name: p.Name},
+		})
+	}
+	for _, cluster := range r.clustersForSecret(ctx, obj) {
+		requests = append(requests, r.clusterPoliciesForCluster(ctx, &cluster)...)
+	}
+	return requests
+}
+
+func hashString(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,165 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	batchv1 "github.com/a8uhnf/opensearch-ism-crd/api/v1"
+	"github.com/a8uhnf/opensearch-ism-crd/internal/metrics"
+	"github.com/a8uhnf/opensearch-ism-crd/internal/pkg/guardrails"
+	"github.com/a8uhnf/opensearch-ism-crd/internal/pkg/targets"
+)
+
+// policyFinalizer keeps a deleted policy object until its ISM policy is
+// deleted from OpenSearch.
+const policyFinalizer = "batch.a8uhnf.com/index-policy"
+
+const (
+	eventDeleted      = "Deleted"
+	eventDeleteFailed = "DeleteFailed"
+	eventOrphaned     = "Orphaned"
+)
+
+// ensureFinalizer adds the finalizer to a policy object that does not have it yet.
+func (r *OSIndexPolicyReconciler) ensureFinalizer(ctx context.Context, policy batchv1.IndexPolicyObject) error {
+	if controllerutil.ContainsFinalizer(policy, policyFinalizer) {
+		return nil
+	}
+	original := policy.DeepCopyObject().(client.Object)
+	controllerutil.AddFinalizer(policy, policyFinalizer)
+	return r.Patch(ctx, policy, client.MergeFromWithOptions(original, client.MergeFromWithOptimisticLock{}))
+}
+
+// finalize deletes the ISM policy of a deleted object from OpenSearch, or from
+// every target, then removes the finalizer. Failures keep the finalizer and are
+// retried with backoff; deletion_policy Retain lets the object go and leaves the
+// ISM policy behind.
+func (r *OSIndexPolicyReconciler) finalize(ctx context.Context, policy batchv1.IndexPolicyObject) (ctrl.Result, error) {
+	if !controllerutil.ContainsFinalizer(policy, policyFinalizer) {
+		return ctrl.Result{}, nil
+	}
+	if err := r.deleteIndexPolicy(ctx, policy); err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to delete index policy from OpenSearch", "name", policy.GetName())
+		return ctrl.Result{}, err
+	}
+
+	original := policy.DeepCopyObject().(client.Object)
+	controllerutil.RemoveFinalizer(policy, policyFinalizer)
+	if err := r.Patch(ctx, policy, client.MergeFromWithOptions(original, client.MergeFromWithOptimisticLock{})); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	key := policyKey(policy)
+	r.releaseClients(ctx, key)
+	metrics.Forget(key)
+	return ctrl.Result{}, nil
+}
+
+// deleteIndexPolicy deletes the ISM policy of the object from its cluster or
+// its targets, unless the object is paused, retains it or is in plan mode.
+func (r *OSIndexPolicyReconciler) deleteIndexPolicy(ctx context.Context, policy batchv1.IndexPolicyObject) error {
+	logr := logf.FromContext(ctx)
+	spec := policy.GetSpec().DeepCopy()
+	if r.NamespacePrefix {
+		guardrails.WithNamespacePrefix(spec, policy.GetNamespace())
+	}
+	policyID := spec.PolicyID
+
+	if isPaused(policy) {
+		logr.Info("OSIndexPolicy paused, leaving index policy in OpenSearch", "policyName", policyID)
+		r.Recorder.Eventf(policy, corev1.EventTypeNormal, eventOrphaned, "Paused, index policy %s was left in OpenSearch", policyID)
+		return nil
+	}
+	if spec.DeletionPolicy == batchv1.DeletionPolicyRetain {
+		logr.Info("Deletion policy Retain, leaving index policy in OpenSearch", "policyName", policyID)
+		r.Recorder.Eventf(policy, corev1.EventTypeNormal, eventOrphaned, "Deletion policy Retain, index policy %s was left in OpenSearch", policyID)
+		return nil
+	}
+	if r.planning(policy) {
+		logr.Info("Planned index policy deletion, not applying it", "policyName", policyID)
+		r.Recorder.Event(policy, corev1.EventTypeNormal, eventPlanned, planMessage(policyID, batchv1.PlanDelete, nil))
+		return nil
+	}
+	if spec.Targets == nil {
+		return r.deleteFromCluster(ctx, policy, policyID)
+	}
+
+	// Clusters that no longer exist are skipped, their ISM policy cannot be reached.
+	clusters, _, err := targets.Select(ctx, r, r.referenceNamespace(policy), spec.Targets)
+	if err != nil {
+		return err
+	}
+	previous := map[string]batchv1.TargetStatus{}
+	for _, target := range policy.GetStatus().Targets {
+		previous[target.Cluster] = target
+	}
+	var errs []error
+	for i := range clusters {
+		cluster := &clusters[i]
+		target := targetPolicy(policy, cluster, previous[cluster.Name])
+		if err := r.deleteFromCluster(withTarget(ctx, cluster.Name), target, policyID); err != nil {
+			errs = append(errs, fmt.Errorf("OpenSearchCluster %s: %w", cluster.Name, err))
+		}
+	}
+	return kerrors.NewAggregate(errs)
+}
+
+// deleteFromCluster deletes the ISM policy from the cluster of
+// spec.opensearch_connection when the object owns it. Without the Secrets of the
+// connection, e.g. deleted with the namespace, the ISM policy is left behind.
+func (r *OSIndexPolicyReconciler) deleteFromCluster(ctx context.Context, policy batchv1.IndexPolicyObject, policyID string) error {
+	logr := logf.FromContext(ctx)
+	cluster := policy.GetSpec().OpensearhConnection.URL
+	opensearchClient, err := r.openSearchClient(ctx, policy)
+	if errors.IsNotFound(err) {
+		logr.Info("Connection Secret not found, leaving index policy in OpenSearch", "policyName", policyID, "cluster", cluster, "error", err.Error())
+		r.Recorder.Eventf(policy, corev1.EventTypeWarning, eventOrphaned, "Index policy %s was left in %s: %v", policyID, cluster, err)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	remotePolicy, err := opensearchClient.GetIndexPolicy(ctx, policyID)
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	// Leave policies this object never took over, e.g. with adoption_policy Fail, to their owner.
+	if !ownsPolicy(policy, remotePolicy) {
+		logr.Info("Index policy not owned, leaving it in OpenSearch", "policyName", policyID, "cluster", cluster)
+		r.Recorder.Eventf(policy, corev1.EventTypeNormal, eventOrphaned, "Index policy %s in %s is not managed by this object and was left in OpenSearch", policyID, cluster)
+		return nil
+	}
+	if err := opensearchClient.DeleteIndexPolicy(ctx, policyID); err != nil {
+		r.Recorder.Eventf(policy, corev1.EventTypeWarning, eventDeleteFailed, "Failed to delete index policy %s from %s: %v", policyID, cluster, err)
+		return err
+	}
+	logr.Info("Index policy deleted from OpenSearch", "policyName", policyID, "cluster", cluster)
+	r.Recorder.Eventf(policy, corev1.EventTypeNormal, eventDeleted, "Deleted index policy %s from %s", policyID, cluster)
+	return nil
+}
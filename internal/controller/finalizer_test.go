@@ -0,0 +1,235 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	batchv1 "github.com/a8uhnf/opensearch-ism-crd/api/v1"
+	"github.com/a8uhnf/opensearch-ism-crd/internal/pkg/opensearch"
+)
+
+// ismPolicies is a minimal OpenSearch serving one ISM policy, whose
+// description is given, and recording its deletion.
+type ismPolicies struct {
+	*httptest.Server
+	mu      sync.Mutex
+	deleted []string
+}
+
+func newISMPolicies(policyID, description string) *ismPolicies {
+	s := &ismPolicies{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		path := "/_plugins/_ism/policies/" + policyID
+		switch {
+		case r.URL.Path == "/":
+			_, _ = w.Write([]byte(`{"version":{"distribution":"opensearch","number":"2.11.0"}}`))
+		case r.URL.Path == path && r.Method == http.MethodDelete:
+			s.deleted = append(s.deleted, policyID)
+		case r.URL.Path == path && len(s.deleted) == 0:
+			_, _ = w.Write([]byte(`{"_id":"` + policyID + `","_seq_no":1,"_primary_term":1,"policy":{"description":"` + description + `"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	return s
+}
+
+func (s *ismPolicies) deletions() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.deleted
+}
+
+var _ = Describe("Policy finalizer", func() {
+	var (
+		server   *ismPolicies
+		policy   *batchv1.OSIndexPolicy
+		recorder *record.FakeRecorder
+		key      = types.NamespacedName{Namespace: "default", Name: "logs"}
+	)
+
+	BeforeEach(func() {
+		server = newISMPolicies("logs", "logs [managed-by osindexpolicy default/logs]")
+		recorder = record.NewFakeRecorder(10)
+		now := metav1.Now()
+		policy = &batchv1.OSIndexPolicy{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:         "default",
+				Name:              "logs",
+				Annotations:       map[string]string{},
+				Finalizers:        []string{policyFinalizer},
+				DeletionTimestamp: &now,
+			},
+			Spec: batchv1.OSIndexPolicySpec{
+				PolicyID:            "logs",
+				OpensearhConnection: batchv1.OpensearhConnection{URL: server.URL},
+			},
+		}
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	reconcileDeleted := func(reconciler *OSIndexPolicyReconciler, objects ...client.Object) {
+		scheme := runtime.NewScheme()
+		Expect(batchv1.AddToScheme(scheme)).To(Succeed())
+		Expect(corev1.AddToScheme(scheme)).To(Succeed())
+		reconciler.Client = fake.NewClientBuilder().WithScheme(scheme).WithObjects(append(objects, policy)...).WithStatusSubresource(policy).Build()
+		reconciler.Clients = opensearch.NewClientCache()
+		reconciler.Recorder = recorder
+
+		_, err := reconciler.Reconcile(context.Background(), reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		// The object goes away with its finalizer.
+		Expect(errors.IsNotFound(reconciler.Get(context.Background(), key, &batchv1.OSIndexPolicy{}))).To(BeTrue())
+	}
+
+	It("adds the finalizer before syncing", func() {
+		policy.DeletionTimestamp = nil
+		policy.Finalizers = nil
+		// The guardrail stops the reconcile before it reaches OpenSearch.
+		guardrail := &batchv1.OSIndexPolicyGuardrail{
+			ObjectMeta: metav1.ObjectMeta{Name: "default"},
+			Spec: batchv1.OSIndexPolicyGuardrailSpec{
+				Namespaces:      []string{"default"},
+				AllowedClusters: []string{"https://logs.example.com:9200"},
+			},
+		}
+		scheme := runtime.NewScheme()
+		Expect(batchv1.AddToScheme(scheme)).To(Succeed())
+		reconciler := &OSIndexPolicyReconciler{
+			Client:   fake.NewClientBuilder().WithScheme(scheme).WithObjects(policy, guardrail).WithStatusSubresource(policy).Build(),
+			Recorder: recorder,
+		}
+
+		_, err := reconciler.Reconcile(context.Background(), reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		stored := &batchv1.OSIndexPolicy{}
+		Expect(reconciler.Get(context.Background(), key, stored)).To(Succeed())
+		Expect(stored.Finalizers).To(ConsistOf(policyFinalizer))
+	})
+
+	It("deletes the owned index policy from OpenSearch", func() {
+		reconcileDeleted(&OSIndexPolicyReconciler{})
+		Expect(server.deletions()).To(ConsistOf("logs"))
+		Expect(recorder.Events).To(Receive(HavePrefix("Normal Deleted Deleted index policy logs")))
+	})
+
+	It("deletes the namespace-prefixed index policy", func() {
+		server.Close()
+		server = newISMPolicies("default-logs", "logs [managed-by osindexpolicy default/logs]")
+		policy.Spec.OpensearhConnection.URL = server.URL
+
+		reconcileDeleted(&OSIndexPolicyReconciler{NamespacePrefix: true})
+		Expect(server.deletions()).To(ConsistOf("default-logs"))
+	})
+
+	It("leaves index policies owned by another object", func() {
+		server.Close()
+		server = newISMPolicies("logs", "logs [managed-by osindexpolicy default/other]")
+		policy.Spec.OpensearhConnection.URL = server.URL
+
+		reconcileDeleted(&OSIndexPolicyReconciler{})
+		Expect(server.deletions()).To(BeEmpty())
+		Expect(recorder.Events).To(Receive(HavePrefix("Normal Orphaned")))
+	})
+
+	It("leaves the index policy of paused objects", func() {
+		policy.Annotations[batchv1.AnnotationPaused] = "true"
+
+		reconcileDeleted(&OSIndexPolicyReconciler{})
+		Expect(server.deletions()).To(BeEmpty())
+	})
+
+	It("leaves the index policy with deletion_policy Retain, even when unreachable", func() {
+		server.Close()
+		policy.Spec.DeletionPolicy = batchv1.DeletionPolicyRetain
+
+		reconcileDeleted(&OSIndexPolicyReconciler{})
+		Expect(server.deletions()).To(BeEmpty())
+		Expect(recorder.Events).To(Receive(HavePrefix("Normal Orphaned Deletion policy Retain")))
+	})
+
+	It("gives up once the connection Secret is gone", func() {
+		policy.Spec.OpensearhConnection.CredentialsSecretRef = &corev1.LocalObjectReference{Name: "opensearch-credentials"}
+
+		reconcileDeleted(&OSIndexPolicyReconciler{})
+		Expect(server.deletions()).To(BeEmpty())
+		Expect(recorder.Events).To(Receive(HavePrefix("Warning Orphaned Index policy logs was left in")))
+	})
+
+	It("only plans the deletion in plan mode", func() {
+		reconcileDeleted(&OSIndexPolicyReconciler{DryRun: true})
+		Expect(server.deletions()).To(BeEmpty())
+		Expect(recorder.Events).To(Receive(Equal("Normal Planned Plan: Delete index policy logs")))
+	})
+
+	It("deletes the index policy from every target", func() {
+		other := newISMPolicies("logs", "logs [managed-by osindexpolicy default/logs]")
+		defer other.Close()
+		eu := &batchv1.OpenSearchCluster{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "eu"},
+			Spec:       batchv1.OpenSearchClusterSpec{OpensearhConnection: batchv1.OpensearhConnection{URL: server.URL}},
+		}
+		us := &batchv1.OpenSearchCluster{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "us"},
+			Spec:       batchv1.OpenSearchClusterSpec{OpensearhConnection: batchv1.OpensearhConnection{URL: other.URL}},
+		}
+		policy.Spec.OpensearhConnection = batchv1.OpensearhConnection{}
+		policy.Spec.Targets = &batchv1.PolicyTargets{Clusters: []string{"eu", "us", "ap"}}
+
+		reconcileDeleted(&OSIndexPolicyReconciler{}, eu, us)
+		Expect(server.deletions()).To(ConsistOf("logs"))
+		Expect(other.deletions()).To(ConsistOf("logs"))
+	})
+
+	It("keeps the finalizer while the deletion fails", func() {
+		server.Close()
+		scheme := runtime.NewScheme()
+		Expect(batchv1.AddToScheme(scheme)).To(Succeed())
+		reconciler := &OSIndexPolicyReconciler{
+			Client:   fake.NewClientBuilder().WithScheme(scheme).WithObjects(policy).WithStatusSubresource(policy).Build(),
+			Clients:  opensearch.NewClientCache(),
+			Recorder: recorder,
+		}
+
+		_, err := reconciler.Reconcile(context.Background(), reconcile.Request{NamespacedName: key})
+		Expect(err).To(HaveOccurred())
+		stored := &batchv1.OSIndexPolicy{}
+		Expect(reconciler.Get(context.Background(), key, stored)).To(Succeed())
+		Expect(stored.Finalizers).To(ConsistOf(policyFinalizer))
+	})
+})
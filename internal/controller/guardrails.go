@@ -0,0 +1,87 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	batchv1 "github.com/a8uhnf/opensearch-ism-crd/api/v1"
+	"github.com/a8uhnf/opensearch-ism-crd/internal/pkg/guardrails"
+)
+
+const eventGuardrailViolation = "GuardrailViolation"
+
+// +kubebuilder:rbac:groups=batch.a8uhnf.com,resources=osindexpolicyguardrails,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
+
+// guardrailViolations double-checks the OSIndexPolicyGuardrails enforced by the
+// webhook, which may have changed since the policy was admitted, and with
+// --namespace-prefix that the policy stays out of the prefix of namespaces
+// created since. ClusterOSIndexPolicies are not restricted.
+func (r *OSIndexPolicyReconciler) guardrailViolations(ctx context.Context, policy batchv1.IndexPolicyObject) ([]string, error) {
+	if policy.GetNamespace() == "" {
+		return nil, nil
+	}
+	violations, err := guardrails.Check(ctx, r, policy.GetNamespace(), policy.GetSpec())
+	if err != nil || !r.NamespacePrefix {
+		return violations, err
+	}
+	prefixViolations, err := guardrails.CheckPrefix(ctx, r, policy.GetNamespace(), policy.GetSpec())
+	return append(violations, prefixViolations...), err
+}
+
+// refuseGuardrails records that the policy breaks the guardrails of its
+// namespace, leaving OpenSearch untouched, and requeues it for the next resync.
+func (r *OSIndexPolicyReconciler) refuseGuardrails(ctx context.Context, policy batchv1.IndexPolicyObject, violations []string) ctrl.Result {
+	logr := logf.FromContext(ctx)
+	message := "policy breaks the guardrails of its namespace: " + strings.Join(violations, "; ")
+	logr.Info("Index policy breaks namespace guardrails, skipping sync", "policyName", policy.GetName(), "violations", violations)
+	setSynced(policy, metav1.ConditionFalse, eventGuardrailViolation, message)
+	r.Recorder.Event(policy, corev1.EventTypeWarning, eventGuardrailViolation, message)
+	return r.resyncAfter(policy)
+}
+
+// policiesForGuardrail requeues every OSIndexPolicy in the namespaces the
+// guardrail applies to.
+func (r *OSIndexPolicyReconciler) policiesForGuardrail(ctx context.Context, obj client.Object) []reconcile.Request {
+	guardrail, ok := obj.(*batchv1.OSIndexPolicyGuardrail)
+	if !ok {
+		return nil
+	}
+	policies := &batchv1.OSIndexPolicyList{}
+	if err := r.List(ctx, policies); err != nil {
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, p := range policies.Items {
+		if guardrails.AppliesTo(guardrail, p.Namespace) {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: p.Namespace, Name: p.Name},
+			})
+		}
+	}
+	return requests
+}
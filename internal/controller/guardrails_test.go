@@ -0,0 +1,157 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	batchv1 "github.com/a8uhnf/opensearch-ism-crd/api/v1"
+)
+
+var _ = Describe("Namespace guardrails", func() {
+	var (
+		reconciler *OSIndexPolicyReconciler
+		guardrail  *batchv1.OSIndexPolicyGuardrail
+	)
+
+	BeforeEach(func() {
+		guardrail = &batchv1.OSIndexPolicyGuardrail{
+			ObjectMeta: metav1.ObjectMeta{Name: "teams"},
+			Spec: batchv1.OSIndexPolicyGuardrailSpec{
+				Namespaces:                  []string{"team-*"},
+				AllowedIndexPatternPrefixes: []string{"team-a-"},
+			},
+		}
+		policy := &batchv1.OSIndexPolicy{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "logs"},
+			Spec: batchv1.OSIndexPolicySpec{
+				PolicyID: "logs",
+				Policy: batchv1.OpensearchIndexPolicy{
+					ISMTemplate: &batchv1.ISMTemplate{IndexPatterns: []string{"*"}},
+				},
+				OpensearhConnection: batchv1.OpensearhConnection{URL: "http://opensearch.invalid:9200"},
+			},
+		}
+		scheme := runtime.NewScheme()
+		Expect(batchv1.AddToScheme(scheme)).To(Succeed())
+		reconciler = &OSIndexPolicyReconciler{
+			Client:   fake.NewClientBuilder().WithScheme(scheme).WithObjects(policy, guardrail).WithStatusSubresource(policy).Build(),
+			Recorder: record.NewFakeRecorder(10),
+		}
+	})
+
+	It("refuses to sync policies breaking the guardrails without calling OpenSearch", func() {
+		_, err := reconciler.Reconcile(context.Background(), reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "team-a", Name: "logs"}})
+		Expect(err).NotTo(HaveOccurred())
+
+		refused := &batchv1.OSIndexPolicy{}
+		Expect(reconciler.Get(context.Background(), types.NamespacedName{Namespace: "team-a", Name: "logs"}, refused)).To(Succeed())
+		synced := meta.FindStatusCondition(refused.Status.Conditions, batchv1.ConditionSynced)
+		Expect(synced).NotTo(BeNil())
+		Expect(synced.Reason).To(Equal(eventGuardrailViolation))
+		Expect(synced.Message).To(ContainSubstring(`index pattern "*"`))
+	})
+
+	It("requeues the policies of the namespaces a guardrail applies to", func() {
+		Expect(reconciler.policiesForGuardrail(context.Background(), guardrail)).To(ConsistOf(
+			reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "team-a", Name: "logs"}},
+		))
+		guardrail.Spec.Namespaces = []string{"platform"}
+		Expect(reconciler.policiesForGuardrail(context.Background(), guardrail)).To(BeEmpty())
+	})
+})
+
+var _ = Describe("Namespace prefix", func() {
+	It("shows the prefixed values in status without changing the spec", func() {
+		policy := &batchv1.OSIndexPolicy{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "logs"},
+			Spec: batchv1.OSIndexPolicySpec{
+				PolicyID: "logs-retention",
+				Policy: batchv1.OpensearchIndexPolicy{
+					ISMTemplate: &batchv1.ISMTemplate{IndexPatterns: []string{"logs-*"}},
+				},
+				OpensearhConnection: batchv1.OpensearhConnection{URL: "http://opensearch.invalid:9200"},
+			},
+		}
+		// The guardrail stops the reconcile before it reaches OpenSearch.
+		guardrail := &batchv1.OSIndexPolicyGuardrail{
+			ObjectMeta: metav1.ObjectMeta{Name: "team-a"},
+			Spec: batchv1.OSIndexPolicyGuardrailSpec{
+				Namespaces:      []string{"team-a"},
+				AllowedClusters: []string{"https://logs.example.com:9200"},
+			},
+		}
+		scheme := runtime.NewScheme()
+		Expect(batchv1.AddToScheme(scheme)).To(Succeed())
+		Expect(corev1.AddToScheme(scheme)).To(Succeed())
+		reconciler := &OSIndexPolicyReconciler{
+			Client:          fake.NewClientBuilder().WithScheme(scheme).WithObjects(policy, guardrail).WithStatusSubresource(policy).Build(),
+			Recorder:        record.NewFakeRecorder(10),
+			NamespacePrefix: true,
+		}
+
+		_, err := reconciler.Reconcile(context.Background(), reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "team-a", Name: "logs"}})
+		Expect(err).NotTo(HaveOccurred())
+
+		stored := &batchv1.OSIndexPolicy{}
+		Expect(reconciler.Get(context.Background(), types.NamespacedName{Namespace: "team-a", Name: "logs"}, stored)).To(Succeed())
+		Expect(stored.Spec.PolicyID).To(Equal("logs-retention"))
+		Expect(stored.Status.EffectivePolicyID).To(Equal("team-a-logs-retention"))
+		Expect(stored.Status.EffectiveIndexPatterns).To(Equal([]string{"team-a-logs-*"}))
+	})
+	It("refuses policies reaching into the prefix of another namespace", func() {
+		policy := &batchv1.OSIndexPolicy{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "team", Name: "logs"},
+			Spec: batchv1.OSIndexPolicySpec{
+				PolicyID:            "a-logs",
+				OpensearhConnection: batchv1.OpensearhConnection{URL: "http://opensearch.invalid:9200"},
+			},
+		}
+		scheme := runtime.NewScheme()
+		Expect(batchv1.AddToScheme(scheme)).To(Succeed())
+		Expect(corev1.AddToScheme(scheme)).To(Succeed())
+		reconciler := &OSIndexPolicyReconciler{
+			Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(policy,
+				&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team"}},
+				&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-a"}},
+			).WithStatusSubresource(policy).Build(),
+			Recorder:        record.NewFakeRecorder(10),
+			NamespacePrefix: true,
+		}
+
+		_, err := reconciler.Reconcile(context.Background(), reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "team", Name: "logs"}})
+		Expect(err).NotTo(HaveOccurred())
+
+		refused := &batchv1.OSIndexPolicy{}
+		Expect(reconciler.Get(context.Background(), types.NamespacedName{Namespace: "team", Name: "logs"}, refused)).To(Succeed())
+		synced := meta.FindStatusCondition(refused.Status.Conditions, batchv1.ConditionSynced)
+		Expect(synced).NotTo(BeNil())
+		Expect(synced.Reason).To(Equal(eventGuardrailViolation))
+		Expect(synced.Message).To(ContainSubstring(`policy ID "team-a-logs" falls into the prefix of namespace team-a`))
+	})
+})
@@ -0,0 +1,158 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	batchv1 "github.com/a8uhnf/opensearch-ism-crd/api/v1"
+	"github.com/a8uhnf/opensearch-ism-crd/internal/metrics"
+	"github.com/a8uhnf/opensearch-ism-crd/internal/pkg/opensearch"
+)
+
+const (
+	// defaultExplainInterval is used when the reconciler has no ExplainInterval.
+	defaultExplainInterval = time.Minute
+	// maxFailedIndices bounds the failed indices listed in status.
+	maxFailedIndices = 20
+	// initializingState groups the indices ISM has not initialized yet.
+	initializingState = "initializing"
+)
+
+// observeManagedIndices queries the explain API, at most once per explain
+// interval, and records the state of the managed indices in status and metrics.
+// It returns the managed indices, or false when the sweep was skipped.
+// Explain failures are logged only, they do not affect the sync.
+func (r *OSIndexPolicyReconciler) observeManagedIndices(ctx context.Context, opensearchClient opensearch.OpenSearch, policy batchv1.IndexPolicyObject) ([]opensearch.ManagedIndex, bool) {
+	interval := r.ExplainInterval
+	if interval <= 0 {
+		interval = defaultExplainInterval
+	}
+	if last := policy.GetStatus().ManagedIndices; last != nil && time.Since(last.LastExplainTime.Time) < interval {
+		return nil, false
+	}
+
+	indices, err := opensearchClient.ExplainPolicy(ctx, policy.GetSpec().PolicyID, managedIndexPatterns(policy.GetSpec()))
+	if err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to explain managed indices", "policyName", policy.GetName())
+		return nil, false
+	}
+	status := summarizeManagedIndices(indices)
+	// The time only moves when the summary changes, so that a steady sweep leaves the status alone.
+	status.LastExplainTime = metav1.Now()
+	if last := policy.GetStatus().ManagedIndices; last != nil && !last.LastExplainTime.IsZero() {
+		unchanged := *status
+		unchanged.LastExplainTime = last.LastExplainTime
+		if equality.Semantic.DeepEqual(last, &unchanged) {
+			status.LastExplainTime = last.LastExplainTime
+		}
+	}
+	policy.GetStatus().ManagedIndices = status
+	if rollout := policy.GetStatus().Rollout; rollout != nil {
+		rollout.Total = len(indices)
+		rollout.Completed = 0
+		for _, index := range indices {
+			if index.PolicySeqNo >= rollout.PolicySeqNo {
+				rollout.Completed++
+			}
+		}
+	}
+	metrics.ManagedIndexFailures.WithLabelValues(policyKey(policy), policy.GetSpec().OpensearhConnection.URL).Set(float64(status.FailedCount))
+
+	condition := metav1.Condition{
+		Type:               batchv1.ConditionIndicesHealthy,
+		Status:             metav1.ConditionTrue,
+		Reason:             "NoFailures",
+		Message:            fmt.Sprintf("%d managed indices, none failed", status.Total),
+		ObservedGeneration: policy.GetGeneration(),
+	}
+	if status.FailedCount > 0 {
+		names := make([]string, 0, len(status.FailedIndices))
+		for _, failed := range status.FailedIndices {
+			names = append(names, failed.Index)
+		}
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "IndicesFailed"
+		condition.Message = fmt.Sprintf("%d of %d managed indices failed: %s", status.FailedCount, status.Total, strings.Join(names, ", "))
+	}
+	meta.SetStatusCondition(&policy.GetStatus().Conditions, condition)
+	return indices, true
+}
+
+// managedIndexPatterns returns the patterns of the indices the policy manages:
+// those of its ISM template and of attach_existing. Without any, the indices
+// attached to the policy by hand may be anywhere and nil is returned.
+func managedIndexPatterns(spec *batchv1.OSIndexPolicySpec) []string {
+	var patterns []string
+	if template := spec.Policy.ISMTemplate; template != nil {
+		patterns = append(patterns, template.IndexPatterns...)
+	}
+	if attach := spec.AttachExisting; attach != nil {
+		patterns = append(patterns, attach.IndexPatterns...)
+	}
+	return patterns
+}
+
+// summarizeManagedIndices counts the indices per state and lists the failed ones.
+func summarizeManagedIndices(indices []opensearch.ManagedIndex) *batchv1.ManagedIndicesStatus {
+	status := &batchv1.ManagedIndicesStatus{Total: len(indices)}
+	states := map[string]*batchv1.ManagedIndexStateStatus{}
+	for _, index := range indices {
+		name := index.State
+		if name == "" {
+			name = initializingState
+		}
+		state, ok := states[name]
+		if !ok {
+			state = &batchv1.ManagedIndexStateStatus{Name: name}
+			states[name] = state
+		}
+		state.Count++
+		if !index.StateStartTime.IsZero() && (state.OldestSince == nil || index.StateStartTime.Before(state.OldestSince.Time)) {
+			since := metav1.NewTime(index.StateStartTime)
+			state.OldestIndex = index.Index
+			state.OldestSince = &since
+		}
+
+		if index.Failed {
+			status.FailedCount++
+			// Indices are sorted by name, so the list is stable across sweeps.
+			if len(status.FailedIndices) < maxFailedIndices {
+				status.FailedIndices = append(status.FailedIndices, batchv1.FailedIndex{
+					Index:   index.Index,
+					State:   index.State,
+					Action:  index.Action,
+					Message: index.Message,
+				})
+			}
+		}
+	}
+	for _, state := range states {
+		status.States = append(status.States, *state)
+	}
+	sort.Slice(status.States, func(i, j int) bool { return status.States[i].Name < status.States[j].Name })
+	return status
+}
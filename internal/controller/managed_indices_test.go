@@ -0,0 +1,53 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/a8uhnf/opensearch-ism-crd/internal/pkg/opensearch"
+)
+
+var _ = Describe("Managed indices summary", func() {
+	It("counts indices per state and lists the failed ones", func() {
+		old := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+		recent := old.Add(24 * time.Hour)
+
+		status := summarizeManagedIndices([]opensearch.ManagedIndex{
+			{Index: "logs-000001", State: "warm", StateStartTime: old},
+			{Index: "logs-000002", State: "warm", StateStartTime: recent},
+			{Index: "logs-000003", State: "hot", StateStartTime: recent, Action: "rollover", Failed: true, Message: "Missing rollover_alias"},
+			{Index: "logs-000004"},
+		})
+
+		Expect(status.Total).To(Equal(4))
+		Expect(status.States).To(HaveLen(3))
+		Expect(status.States[0].Name).To(Equal("hot"))
+		Expect(status.States[1].Name).To(Equal(initializingState))
+		Expect(status.States[1].OldestSince).To(BeNil())
+		Expect(status.States[2].Name).To(Equal("warm"))
+		Expect(status.States[2].Count).To(Equal(2))
+		Expect(status.States[2].OldestIndex).To(Equal("logs-000001"))
+		Expect(status.States[2].OldestSince.Time).To(Equal(old))
+		Expect(status.FailedCount).To(Equal(1))
+		Expect(status.FailedIndices).To(HaveLen(1))
+		Expect(status.FailedIndices[0].Message).To(Equal("Missing rollover_alias"))
+	})
+})
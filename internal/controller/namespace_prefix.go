@@ -0,0 +1,49 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	batchv1 "github.com/a8uhnf/opensearch-ism-crd/api/v1"
+	"github.com/a8uhnf/opensearch-ism-crd/internal/pkg/guardrails"
+	"github.com/a8uhnf/opensearch-ism-crd/internal/pkg/render"
+)
+
+// applyNamespacePrefix prefixes the policy ID and index patterns of a
+// namespaced policy with its namespace when the manager runs with
+// --namespace-prefix. The spec is changed in memory only, and before the copy
+// status patches are computed against, so the stored spec is never written.
+func (r *OSIndexPolicyReconciler) applyNamespacePrefix(policy batchv1.IndexPolicyObject) {
+	if r.NamespacePrefix {
+		guardrails.WithNamespacePrefix(policy.GetSpec(), policy.GetNamespace())
+	}
+}
+
+// recordEffectiveSpec shows the policy ID, index patterns and policy hash sent
+// to OpenSearch in status, and the whole policy when it is composed.
+func recordEffectiveSpec(policy batchv1.IndexPolicyObject) {
+	spec, status := policy.GetSpec(), policy.GetStatus()
+	status.EffectivePolicyID = spec.PolicyID
+	status.PolicyHash = render.Hash(&spec.Policy)
+	status.EffectiveIndexPatterns = nil
+	if template := spec.Policy.ISMTemplate; template != nil && len(template.IndexPatterns) > 0 {
+		status.EffectiveIndexPatterns = append([]string(nil), template.IndexPatterns...)
+	}
+	status.ResolvedPolicy = nil
+	if render.Composed(spec) {
+		status.ResolvedPolicy = spec.Policy.DeepCopy()
+	}
+}
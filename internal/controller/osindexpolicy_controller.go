@@ -18,28 +18,79 @@ package controller
 
 import (
 	"context"
-	"crypto/tls"
 	"fmt"
 	batchv1 "github.com/a8uhnf/opensearch-ism-crd/api/v1"
+	"github.com/a8uhnf/opensearch-ism-crd/internal/metrics"
 	"github.com/a8uhnf/opensearch-ism-crd/internal/pkg/opensearch"
+	"github.com/a8uhnf/opensearch-ism-crd/internal/pkg/render"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
-	"net/http"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"strings"
+	"time"
 )
 
 // OSIndexPolicyReconciler reconciles a OSIndexPolicy object
 type OSIndexPolicyReconciler struct {
 	client.Client
 	Scheme *runtime.Scheme
+	// Clients caches OpenSearch clients across reconciles
+	Clients *opensearch.ClientCache
+	// Recorder emits Events on the reconciled OSIndexPolicies
+	Recorder record.EventRecorder
+	// ExplainInterval is the minimum time between two explain sweeps of a policy
+	ExplainInterval time.Duration
+	// DryRun plans the changes to OpenSearch of every object without making them
+	DryRun bool
+	// ResyncInterval is the time between two drift checks of a policy, unless
+	// the policy sets spec.resync_interval
+	ResyncInterval time.Duration
+	// ErrorBackoff and MaxErrorBackoff bound the exponential backoff of failed reconciles
+	ErrorBackoff    time.Duration
+	MaxErrorBackoff time.Duration
+	// NamespacePrefix prefixes the ISM policy ID and index patterns of every
+	// OSIndexPolicy with its namespace
+	NamespacePrefix bool
+	// ClusterSecretNamespace is the namespace the Secrets and templates
+	// referenced by ClusterOSIndexPolicies are read from
+	ClusterSecretNamespace string
 }
 
+const (
+	// defaultResyncInterval is used when the reconciler has no ResyncInterval.
+	defaultResyncInterval = 5 * time.Minute
+	// defaultErrorBackoff and defaultMaxErrorBackoff are used when the reconciler has no error backoff.
+	defaultErrorBackoff    = time.Second
+	defaultMaxErrorBackoff = 5 * time.Minute
+)
+
+// Reasons of the Events emitted on OSIndexPolicies.
+const (
+	eventCreated            = "Created"
+	eventUpdated            = "Updated"
+	eventDriftCorrected     = "DriftCorrected"
+	eventSyncFailed         = "SyncFailed"
+	eventClusterUnreachable = "ClusterUnreachable"
+)
+
 // +kubebuilder:rbac:groups=batch.a8uhnf.com,resources=osindexpolicies,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=batch.a8uhnf.com,resources=osindexpolicies/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=batch.a8uhnf.com,resources=osindexpolicies/finalizers,verbs=update
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -72,83 +123,401 @@ func (r *OSIndexPolicyReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 		if client.IgnoreNotFound(err) != nil {
 			return ctrl.Result{}, err
 		}
-		// Resource not found, return and don't requeue
+		// Resource not found, drop its cached client and metrics and don't requeue
+		r.releaseClients(ctx, req.String())
+		metrics.Forget(req.String())
 		return ctrl.Result{}, nil
 	}
+	return r.reconcilePolicy(ctx, osIndexPolicy)
+}
 
-	opensearchClient, err := opensearch.NewOpenSearchClient(ctx, opensearch.OpenSearchConfig{
-		URL:      osIndexPolicy.Spec.OpensearhConnection.URL,      // Use the URL from the request spec",
-		Username: osIndexPolicy.Spec.OpensearhConnection.Username, // Use the username from the request spec
-		Password: osIndexPolicy.Spec.OpensearhConnection.Password, // Use the password from the request spec
-		TLSConfig: &http.Transport{
-			TLSClientConfig: &tls.Config{
-				InsecureSkipVerify: true, // Set to true for testing purposes, should be false in production
-			},
-		},
-	})
+// reconcilePolicy moves the ISM policy in OpenSearch towards the spec of an
+// OSIndexPolicy or a ClusterOSIndexPolicy.
+func (r *OSIndexPolicyReconciler) reconcilePolicy(ctx context.Context, policy batchv1.IndexPolicyObject) (ctrl.Result, error) {
+	logr := logf.FromContext(ctx)
+	key := policyKey(policy)
+	if !policy.GetDeletionTimestamp().IsZero() {
+		return r.finalize(ctx, policy)
+	}
+	if err := r.ensureFinalizer(ctx, policy); err != nil {
+		logr.Error(err, "Failed to add the finalizer")
+		return ctrl.Result{}, err
+	}
+	resolveErr := r.resolvePolicy(ctx, policy)
+	r.applyNamespacePrefix(policy)
+
+	// Status changes are patched against the object as read, and only when the
+	// reconcile changed something.
+	original := policy.DeepCopyObject().(batchv1.IndexPolicyObject)
+	// syncTargets records the metrics of every target instead.
+	defer func() {
+		if policy.GetSpec().Targets == nil {
+			metrics.SetSynced(key, policy.GetSpec().OpensearhConnection.URL,
+				meta.IsStatusConditionTrue(policy.GetStatus().Conditions, batchv1.ConditionSynced))
+		}
+	}()
+
+	if isPaused(policy) {
+		return r.pause(ctx, policy, original)
+	}
+	meta.RemoveStatusCondition(&policy.GetStatus().Conditions, batchv1.ConditionPaused)
+	if resolveErr != nil {
+		return r.failResolve(ctx, policy, original, resolveErr)
+	}
+	recordEffectiveSpec(policy)
+	resync := resyncRequested(policy)
+
+	var result ctrl.Result
+	var err error
+	if policy.GetSpec().Targets != nil {
+		result, err = r.syncTargets(ctx, policy, resync)
+	} else {
+		policy.GetStatus().Targets = nil
+		result, err = r.syncCluster(ctx, policy, resync)
+	}
+	if patchErr := r.patchStatus(ctx, policy, original); patchErr != nil {
+		logr.Error(patchErr, "Failed to patch OSIndexPolicy status")
+		if err == nil {
+			return ctrl.Result{}, patchErr
+		}
+	}
+	return result, err
+}
+
+// syncCluster moves the ISM policy in the OpenSearch cluster of
+// spec.opensearch_connection towards the spec, recording the outcome in the
+// status of the policy for the caller to patch.
+func (r *OSIndexPolicyReconciler) syncCluster(ctx context.Context, policy batchv1.IndexPolicyObject, resync bool) (ctrl.Result, error) {
+	logr := logf.FromContext(ctx)
+	key := policyKey(policy)
+
+	violations, err := r.guardrailViolations(ctx, policy)
+	if err != nil {
+		logr.Error(err, "Failed to check namespace guardrails")
+		return ctrl.Result{}, err
+	}
+	if len(violations) > 0 {
+		return r.refuseGuardrails(ctx, policy, violations), nil
+	}
+
+	opensearchClient, err := r.openSearchClient(ctx, policy)
 	if err != nil {
 		logr.Error(err, "Failed to create OpenSearch client")
-		// If the OpenSearch client cannot be created, return an error to requeue the request.
-		return ctrl.Result{
-			RequeueAfter: 30 * 1000000000, // Requeue after 30 seconds
-		}, err
+		// If the OpenSearch client cannot be created, return an error to requeue the request with backoff.
+		return ctrl.Result{}, err
+	}
+
+	clusterInfo, err := opensearchClient.ClusterInfo(ctx)
+	if circuitErr, ok := opensearch.IsCircuitOpen(err); ok {
+		return r.skipUnreachable(ctx, policy, circuitErr), nil
+	}
+	if err != nil {
+		logr.Error(err, "Failed to detect OpenSearch version")
+		r.Recorder.Eventf(policy, corev1.EventTypeWarning, eventClusterUnreachable, "Failed to reach OpenSearch: %v", err)
+		return ctrl.Result{}, err
 	}
+	policy.GetStatus().ClusterDistribution = clusterInfo.Distribution
+	policy.GetStatus().ClusterVersion = clusterInfo.Version
 
-	policy, err := opensearchClient.GetIndexPolicy(ctx, osIndexPolicy.Spec.PolicyID)
+	// The webhook can only check actions once the version is recorded in status,
+	// so check them again before pushing anything to OpenSearch.
+	if unsupported := opensearch.UnsupportedActions(clusterInfo, &policy.GetSpec().Policy); len(unsupported) > 0 {
+		message := fmt.Sprintf("actions not supported by %s: %s", clusterInfo, strings.Join(unsupported, ", "))
+		logr.Info("Index policy uses unsupported actions, skipping sync", "policyName", policy.GetName(), "actions", unsupported)
+		setSynced(policy, metav1.ConditionFalse, "UnsupportedActions", message)
+		r.Recorder.Event(policy, corev1.EventTypeWarning, eventSyncFailed, message)
+		return r.resyncAfter(policy), nil
+	}
+
+	remotePolicy, err := opensearchClient.GetIndexPolicy(ctx, policy.GetSpec().PolicyID)
+
+	if circuitErr, ok := opensearch.IsCircuitOpen(err); ok {
+		return r.skipUnreachable(ctx, policy, circuitErr), nil
+	}
+	if err == nil || errors.IsNotFound(err) {
+		setReachable(policy, metav1.ConditionTrue, "Connected", "OpenSearch cluster answered")
+	}
 
 	if errors.IsNotFound(err) {
-		logr.Error(err, "Index policy not found in OpenSearch, creating new policy", "policyName", osIndexPolicy.Name)
-
-		if err := opensearchClient.CreateIndexPolicy(ctx, osIndexPolicy.Spec.PolicyID, &osIndexPolicy.Spec.Policy); err != nil {
-			logr.Error(err, "Failed to create index policy in OpenSearch", "policyName", osIndexPolicy.Name)
-			// If the index policy cannot be created, return an error to requeue the request.
-			return ctrl.Result{
-				RequeueAfter: 30 * 1000000000, // Requeue after 30 seconds
-			}, err
-		}
-		logr.Info("Index policy created successfully in OpenSearch", "policyName", osIndexPolicy.Name)
+		logr.Error(err, "Index policy not found in OpenSearch, creating new policy", "policyName", policy.GetName())
 
-		return ctrl.Result{
-			RequeueAfter: 30 * 1000000000, // Requeue after 30 seconds
-		}, nil
+		if r.planning(policy) {
+			r.recordPlan(ctx, policy, batchv1.PlanCreate, []string{"policy"})
+			return r.resyncAfter(policy), nil
+		}
+		desired := opensearch.WithOwner(&policy.GetSpec().Policy, key)
+		if err := opensearchClient.CreateIndexPolicy(ctx, policy.GetSpec().PolicyID, desired); err != nil {
+			logr.Error(err, "Failed to create index policy in OpenSearch", "policyName", policy.GetName())
+			r.Recorder.Eventf(policy, corev1.EventTypeWarning, eventSyncFailed, "Failed to create index policy %s: %v", policy.GetSpec().PolicyID, err)
+			// If the index policy cannot be created, return an error to requeue the request with backoff.
+			return ctrl.Result{}, err
+		}
+		logr.Info("Index policy created successfully in OpenSearch", "policyName", policy.GetName())
+		recordSyncedPolicy(policy, &opensearch.IndexPolicy{ID: policy.GetSpec().PolicyID})
+		setSynced(policy, metav1.ConditionTrue, "Created", "Index policy created in OpenSearch")
+		r.Recorder.Eventf(policy, corev1.EventTypeNormal, eventCreated, "Created index policy %s", policy.GetSpec().PolicyID)
+		return r.resyncAfter(policy), nil
 	}
 
 	if err != nil {
 		logr.Error(err, "Failed to retrieve index policy from OpenSearch")
-		return ctrl.Result{
-			RequeueAfter: 30 * 1000000000, // Requeue after 30 seconds
-		}, err
+		r.Recorder.Eventf(policy, corev1.EventTypeWarning, eventSyncFailed, "Failed to retrieve index policy %s: %v", policy.GetSpec().PolicyID, err)
+		return ctrl.Result{}, err
 	}
 
-	fmt.Println(policy)
-
-	// Here you would add your logic to handle the OSIndexPolicy.
-	logr.Info("Successfully reconciled OSIndexPolicy", "name", osIndexPolicy.Name, "namespace", osIndexPolicy.Namespace)
-	// For example, you might want to check the policy's status and update it accordingly.
-	// This is a placeholder for your reconciliation logic.
-	// You can update the status of the OSIndexPolicy if needed.
-	// osIndexPolicy.Status.SomeField = "some value"
-	if err := r.Status().Update(ctx, osIndexPolicy); err != nil {
-		logr.Error(err, "Failed to update OSIndexPolicy status")
+	if !r.claimPolicy(ctx, policy, remotePolicy) {
+		return r.resyncAfter(policy), nil
+	}
+	if err := r.syncPolicy(ctx, opensearchClient, policy, remotePolicy, resync); err != nil {
 		return ctrl.Result{}, err
 	}
-	// If you want to requeue the request after some time, you can return a Result with RequeueAfter set.
-	// If you want to requeue the request immediately, you can return ctrl.Result{Requeue: true}.
-	// If you want to stop requeuing, return ctrl.Result{}.
-	logr.Info("OSIndexPolicy reconciled successfully", "name", osIndexPolicy.Name)
+	if !r.planning(policy) {
+		if err := r.resumeRollout(ctx, opensearchClient, policy); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	if resync {
+		policy.GetStatus().LastResyncAt = policy.GetAnnotations()[batchv1.AnnotationResyncAt]
+	}
+	if indices, ok := r.observeManagedIndices(ctx, opensearchClient, policy); ok {
+		r.retryFailedIndices(ctx, opensearchClient, policy, indices)
+	}
+	r.attachExistingIndices(ctx, opensearchClient, policy)
 
-	// Returning a result to requeue the request after 30 seconds.
-	logr.Info("Requeuing OSIndexPolicy reconciliation after 30 seconds", "name", osIndexPolicy.Name)
+	logr.Info("Successfully reconciled OSIndexPolicy", "name", policy.GetName(), "namespace", policy.GetNamespace())
+	// Errors are requeued with the exponential backoff of the rate limiter, and
+	// spec changes trigger a reconcile right away. The periodic resync catches
+	// drift in OpenSearch and refreshes the state of the managed indices.
+	logr.Info("OSIndexPolicy reconciled successfully", "name", policy.GetName())
 
-	return ctrl.Result{
-		RequeueAfter: 30 * 1000000000, // Requeue after 30 seconds
-	}, nil
+	result := r.resyncAfter(policy)
+	logr.Info("Requeuing OSIndexPolicy reconciliation", "name", policy.GetName(), "after", result.RequeueAfter)
+
+	return result, nil
+}
+
+// syncPolicy updates the ISM policy in OpenSearch when it differs from the spec.
+// The policy description names the object as its owner. In plan mode the
+// update is only recorded in status.
+// A difference while the rendered policy is unchanged since the last sync is
+// drift. With force, the policy is written even when no difference is found, to
+// replace the fields OpenSearch holds that the comparison does not cover.
+func (r *OSIndexPolicyReconciler) syncPolicy(ctx context.Context, opensearchClient opensearch.OpenSearch, policy batchv1.IndexPolicyObject, remotePolicy *opensearch.IndexPolicy, force bool) error {
+	logr := logf.FromContext(ctx)
+	key := policyKey(policy)
+	desired := opensearch.WithOwner(&policy.GetSpec().Policy, key)
+	diff, err := opensearch.DiffPolicy(desired, remotePolicy.Policy)
+	if err != nil {
+		logr.Error(err, "Failed to compare index policy with OpenSearch", "policyName", policy.GetName())
+		setSynced(policy, metav1.ConditionFalse, "SyncFailed", err.Error())
+		r.Recorder.Eventf(policy, corev1.EventTypeWarning, eventSyncFailed, "Failed to compare index policy %s: %v", policy.GetSpec().PolicyID, err)
+		return err
+	}
+	if r.planning(policy) {
+		operation := batchv1.PlanUpdate
+		if len(diff) == 0 {
+			operation = batchv1.PlanNoOp
+		}
+		r.recordPlan(ctx, policy, operation, diff)
+		return nil
+	}
+	policy.GetStatus().Plan = nil
+	if len(diff) == 0 && !force {
+		recordSyncedPolicy(policy, remotePolicy)
+		if !isSyncedAtGeneration(policy) {
+			setSynced(policy, metav1.ConditionTrue, "InSync", "Index policy in OpenSearch matches the spec")
+		}
+		return nil
+	}
+
+	cluster := policy.GetSpec().OpensearhConnection.URL
+	drift := isDrift(policy)
+	if drift {
+		metrics.DriftDetected.WithLabelValues(key, cluster).Inc()
+	}
+	logr.Info("Index policy differs from spec, updating", "policyName", policy.GetName(), "drift", drift, "fields", diff)
+	updated, err := opensearchClient.UpdateIndexPolicy(ctx, policy.GetSpec().PolicyID, remotePolicy.SeqNo, remotePolicy.PrimaryTerm, desired)
+	if err != nil {
+		logr.Error(err, "Failed to update index policy in OpenSearch", "policyName", policy.GetName())
+		setSynced(policy, metav1.ConditionFalse, "SyncFailed", err.Error())
+		r.Recorder.Eventf(policy, corev1.EventTypeWarning, eventSyncFailed, "Failed to update index policy %s: %v", policy.GetSpec().PolicyID, err)
+		return err
+	}
+	metrics.Updates.WithLabelValues(key, cluster).Inc()
+	recordSyncedPolicy(policy, updated)
+
+	reason := eventUpdated
+	if drift {
+		reason = eventDriftCorrected
+	}
+	message := "Updated fields: " + strings.Join(diff, ", ")
+	summary := diffSummary(diff)
+	if len(diff) == 0 {
+		reason = eventResynced
+		message = "Index policy rewritten on request of the " + batchv1.AnnotationResyncAt + " annotation"
+		summary = "rewritten on request"
+	}
+	setSynced(policy, metav1.ConditionTrue, reason, message)
+	r.Recorder.Eventf(policy, corev1.EventTypeNormal, reason, "Index policy %s: %s", policy.GetSpec().PolicyID, summary)
+	startRollout(policy, updated.SeqNo)
+	return nil
+}
+
+// policyKey names the policy object in owner markers, cached clients and
+// metrics: namespace/name, or the name alone for a cluster-scoped object.
+func policyKey(policy client.Object) string {
+	if policy.GetNamespace() == "" {
+		return policy.GetName()
+	}
+	return client.ObjectKeyFromObject(policy).String()
+}
+
+// maxEventDiffFields bounds the number of changed fields listed in an Event.
+const maxEventDiffFields = 10
+
+// diffSummary lists the changed fields for an Event, truncated to stay readable.
+func diffSummary(diff []string) string {
+	if len(diff) <= maxEventDiffFields {
+		return "updated " + strings.Join(diff, ", ")
+	}
+	return fmt.Sprintf("updated %s and %d more fields", strings.Join(diff[:maxEventDiffFields], ", "), len(diff)-maxEventDiffFields)
+}
+
+// isSyncedAtGeneration reports whether the current generation of the spec was synced.
+func isSyncedAtGeneration(policy batchv1.IndexPolicyObject) bool {
+	synced := meta.FindStatusCondition(policy.GetStatus().Conditions, batchv1.ConditionSynced)
+	return synced != nil && synced.Status == metav1.ConditionTrue && synced.ObservedGeneration == policy.GetGeneration()
+}
+
+// isDrift reports whether the policy rendered from the spec is the one last
+// synced, so that a difference was made in OpenSearch. A template, base or
+// ConfigMap changes the rendered policy without a new generation of the spec.
+func isDrift(policy batchv1.IndexPolicyObject) bool {
+	synced := policy.GetStatus().SyncedPolicy
+	return synced != nil && synced.PolicyID == policy.GetSpec().PolicyID && synced.Hash == render.Hash(&policy.GetSpec().Policy)
+}
+
+// recordSyncedPolicy records the version of the ISM policy that now matches the spec.
+func recordSyncedPolicy(policy batchv1.IndexPolicyObject, remotePolicy *opensearch.IndexPolicy) {
+	policy.GetStatus().SyncedPolicy = &batchv1.SyncedPolicy{
+		PolicyID:    policy.GetSpec().PolicyID,
+		SeqNo:       remotePolicy.SeqNo,
+		PrimaryTerm: remotePolicy.PrimaryTerm,
+		Hash:        render.Hash(&policy.GetSpec().Policy),
+	}
+}
+
+// skipUnreachable records that the cluster's circuit breaker is open and
+// requeues once it lets a probe through, without calling OpenSearch.
+func (r *OSIndexPolicyReconciler) skipUnreachable(ctx context.Context, policy batchv1.IndexPolicyObject, circuitErr *opensearch.CircuitOpenError) ctrl.Result {
+	logr := logf.FromContext(ctx)
+	logr.Info("OpenSearch cluster unreachable, skipping reconciliation", "url", circuitErr.URL, "retryAfter", circuitErr.RetryAfter)
+	setReachable(policy, metav1.ConditionFalse, "CircuitOpen", circuitErr.Error())
+	r.Recorder.Event(policy, corev1.EventTypeWarning, eventClusterUnreachable, circuitErr.Error())
+	return ctrl.Result{RequeueAfter: circuitErr.RetryAfter}
+}
+
+// patchStatus writes the status of the policy when it differs from the status
+// of original, the policy as read. A merge patch does not conflict with writes
+// made to the object since it was read.
+func (r *OSIndexPolicyReconciler) patchStatus(ctx context.Context, policy, original batchv1.IndexPolicyObject) error {
+	if equality.Semantic.DeepEqual(original.GetStatus(), policy.GetStatus()) {
+		return nil
+	}
+	return r.Status().Patch(ctx, policy, client.MergeFrom(original))
+}
+
+// resyncAfter requeues the policy for its next drift check.
+func (r *OSIndexPolicyReconciler) resyncAfter(policy batchv1.IndexPolicyObject) ctrl.Result {
+	if policy.GetSpec().ResyncInterval != nil && policy.GetSpec().ResyncInterval.Duration > 0 {
+		return ctrl.Result{RequeueAfter: policy.GetSpec().ResyncInterval.Duration}
+	}
+	if r.ResyncInterval > 0 {
+		return ctrl.Result{RequeueAfter: r.ResyncInterval}
+	}
+	return ctrl.Result{RequeueAfter: defaultResyncInterval}
+}
+
+// setSynced records whether the ISM policy in OpenSearch matches the spec.
+func setSynced(policy batchv1.IndexPolicyObject, status metav1.ConditionStatus, reason, message string) {
+	meta.SetStatusCondition(&policy.GetStatus().Conditions, metav1.Condition{
+		Type:               batchv1.ConditionSynced,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: policy.GetGeneration(),
+	})
+}
+
+// setReachable records whether the target OpenSearch cluster could be reached.
+func setReachable(policy batchv1.IndexPolicyObject, status metav1.ConditionStatus, reason, message string) {
+	meta.SetStatusCondition(&policy.GetStatus().Conditions, metav1.Condition{
+		Type:               batchv1.ConditionReachable,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: policy.GetGeneration(),
+	})
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *OSIndexPolicyReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if r.Clients == nil {
+		r.Clients = opensearch.NewClientCache()
+	}
+	if r.Recorder == nil {
+		r.Recorder = mgr.GetEventRecorderFor("osindexpolicy-controller")
+	}
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &batchv1.OSIndexPolicy{}, connectionSecretsIndex,
+		func(obj client.Object) []string {
+			return connectionSecretNames(obj.(*batchv1.OSIndexPolicy).Spec.OpensearhConnection)
+		}); err != nil {
+		return err
+	}
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &batchv1.OSIndexPolicy{}, templateRefIndex,
+		func(obj client.Object) []string {
+			return templateRefName(&obj.(*batchv1.OSIndexPolicy).Spec)
+		}); err != nil {
+		return err
+	}
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &batchv1.OSIndexPolicy{}, baseIndex,
+		func(obj client.Object) []string {
+			return baseName(&obj.(*batchv1.OSIndexPolicy).Spec)
+		}); err != nil {
+		return err
+	}
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &batchv1.OSIndexPolicy{}, policyConfigMapIndex,
+		func(obj client.Object) []string {
+			return policyConfigMapName(&obj.(*batchv1.OSIndexPolicy).Spec)
+		}); err != nil {
+		return err
+	}
+	// Status updates do not change the generation, so they do not trigger a
+	// reconcile. Annotation changes do, for the paused and resync-at annotations.
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&batchv1.OSIndexPolicy{}).
+		For(&batchv1.OSIndexPolicy{}, builder.WithPredicates(
+			predicate.Or(predicate.GenerationChangedPredicate{}, predicate.AnnotationChangedPredicate{}))).
+		Watches(&corev1.Secret{}, handler.EnqueueRequestsFromMapFunc(r.policiesForSecret)).
+		Watches(&corev1.ConfigMap{}, handler.EnqueueRequestsFromMapFunc(r.policiesForConfigMap)).
+		Watches(&batchv1.OpenSearchCluster{}, handler.EnqueueRequestsFromMapFunc(r.policiesForCluster)).
+		Watches(&batchv1.OSIndexPolicyGuardrail{}, handler.EnqueueRequestsFromMapFunc(r.policiesForGuardrail)).
+		Watches(&batchv1.OSIndexPolicyTemplate{}, handler.EnqueueRequestsFromMapFunc(r.policiesForTemplate)).
+		Watches(&batchv1.OSIndexPolicy{}, handler.EnqueueRequestsFromMapFunc(r.policiesForBase),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		WithOptions(controller.Options{RateLimiter: r.rateLimiter()}).
 		Named("osindexpolicy").
 		Complete(r)
 }
+
+// rateLimiter backs off failed reconciles exponentially between ErrorBackoff
+// and MaxErrorBackoff.
+func (r *OSIndexPolicyReconciler) rateLimiter() workqueue.TypedRateLimiter[reconcile.Request] {
+	errorBackoff, maxErrorBackoff := r.ErrorBackoff, r.MaxErrorBackoff
+	if errorBackoff <= 0 {
+		errorBackoff = defaultErrorBackoff
+	}
+	if maxErrorBackoff <= 0 {
+		maxErrorBackoff = defaultMaxErrorBackoff
+	}
+	return workqueue.NewTypedItemExponentialFailureRateLimiter[reconcile.Request](errorBackoff, maxErrorBackoff)
+}
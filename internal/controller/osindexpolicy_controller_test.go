@@ -18,18 +18,52 @@ package controller
 
 import (
 	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	batchv1 "github.com/a8uhnf/opensearch-ism-crd/api/v1"
+	"github.com/a8uhnf/opensearch-ism-crd/internal/pkg/opensearch"
 )
 
+// statusWriteCounter is a client counting the writes to the status subresource.
+type statusWriteCounter struct {
+	client.Client
+	writes *int
+}
+
+func (c statusWriteCounter) Status() client.SubResourceWriter {
+	return countingStatusWriter{SubResourceWriter: c.Client.Status(), writes: c.writes}
+}
+
+type countingStatusWriter struct {
+	client.SubResourceWriter
+	writes *int
+}
+
+func (w countingStatusWriter) Update(ctx context.Context, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+	*w.writes++
+	return w.SubResourceWriter.Update(ctx, obj, opts...)
+}
+
+func (w countingStatusWriter) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+	*w.writes++
+	return w.SubResourceWriter.Patch(ctx, obj, patch, opts...)
+}
+
 var _ = Describe("OSIndexPolicy Controller", func() {
 	Context("When reconciling a resource", func() {
 		const resourceName = "test-resource"
@@ -69,8 +103,10 @@ var _ = Describe("OSIndexPolicy Controller", func() {
 		It("should successfully reconcile the resource", func() {
 			By("Reconciling the created resource")
 			controllerReconciler := &OSIndexPolicyReconciler{
-				Client: k8sClient,
-				Scheme: k8sClient.Scheme(),
+				Client:   k8sClient,
+				Scheme:   k8sClient.Scheme(),
+				Clients:  opensearch.NewClientCache(),
+				Recorder: record.NewFakeRecorder(10),
 			}
 
 			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
@@ -81,4 +117,128 @@ var _ = Describe("OSIndexPolicy Controller", func() {
 			// Example: If you expect a certain status condition after reconciliation, verify it here.
 		})
 	})
+
+	Context("When the policy is in sync", func() {
+		const resourceName = "steady-resource"
+
+		ctx := context.Background()
+		typeNamespacedName := types.NamespacedName{Name: resourceName, Namespace: "default"}
+
+		var (
+			server *httptest.Server
+			mu     sync.Mutex
+			stored json.RawMessage
+		)
+
+		BeforeEach(func() {
+			stored = nil
+			// A minimal OpenSearch keeping the policy it is sent.
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				mu.Lock()
+				defer mu.Unlock()
+				switch {
+				case r.URL.Path == "/":
+					_, _ = w.Write([]byte(`{"version":{"distribution":"opensearch","number":"2.11.0"}}`))
+				case r.URL.Path == "/_plugins/_ism/explain":
+					_, _ = w.Write([]byte(`{"total_managed_indices":0}`))
+				case r.Method == http.MethodPut:
+					body, _ := io.ReadAll(r.Body)
+					request := struct {
+						Policy json.RawMessage `json:"policy"`
+					}{}
+					_ = json.Unmarshal(body, &request)
+					stored = request.Policy
+					_ = json.NewEncoder(w).Encode(map[string]interface{}{"_id": "steady", "_seq_no": 0, "_primary_term": 1, "policy": stored})
+				case stored == nil:
+					w.WriteHeader(http.StatusNotFound)
+				default:
+					_ = json.NewEncoder(w).Encode(map[string]interface{}{"_id": "steady", "_seq_no": 0, "_primary_term": 1, "policy": stored})
+				}
+			}))
+
+			resource := &batchv1.OSIndexPolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: resourceName, Namespace: "default"},
+				Spec: batchv1.OSIndexPolicySpec{
+					PolicyID:            "steady",
+					OpensearhConnection: batchv1.OpensearhConnection{URL: server.URL},
+					Policy:              batchv1.OpensearchIndexPolicy{Description: "steady", DefaultState: "hot"},
+				},
+			}
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			resource := &batchv1.OSIndexPolicy{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+
+			By("deleting the policy from OpenSearch before the finalizer lets the object go")
+			controllerReconciler := &OSIndexPolicyReconciler{
+				Client:   k8sClient,
+				Scheme:   k8sClient.Scheme(),
+				Clients:  opensearch.NewClientCache(),
+				Recorder: record.NewFakeRecorder(10),
+			}
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(errors.IsNotFound(k8sClient.Get(ctx, typeNamespacedName, resource))).To(BeTrue())
+			server.Close()
+		})
+
+		It("does not write the status on a steady-state reconcile", func() {
+			statusWrites := 0
+			controllerReconciler := &OSIndexPolicyReconciler{
+				Client:   statusWriteCounter{Client: k8sClient, writes: &statusWrites},
+				Scheme:   k8sClient.Scheme(),
+				Clients:  opensearch.NewClientCache(),
+				Recorder: record.NewFakeRecorder(10),
+			}
+			reconcileOnce := func() string {
+				_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+				Expect(err).NotTo(HaveOccurred())
+				resource := &batchv1.OSIndexPolicy{}
+				Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+				return resource.ResourceVersion
+			}
+
+			By("creating the policy, then recording the managed indices")
+			reconcileOnce()
+			steady := reconcileOnce()
+			Expect(statusWrites).To(Equal(2))
+
+			By("reconciling again with nothing changed")
+			Expect(reconcileOnce()).To(Equal(steady))
+			Expect(reconcileOnce()).To(Equal(steady))
+			Expect(statusWrites).To(Equal(2))
+		})
+
+		It("does not write the status when the explain sweep finds nothing new", func() {
+			statusWrites := 0
+			controllerReconciler := &OSIndexPolicyReconciler{
+				Client:   statusWriteCounter{Client: k8sClient, writes: &statusWrites},
+				Scheme:   k8sClient.Scheme(),
+				Clients:  opensearch.NewClientCache(),
+				Recorder: record.NewFakeRecorder(10),
+				// Every reconcile queries the explain API.
+				ExplainInterval: time.Nanosecond,
+			}
+			reconcileOnce := func() string {
+				_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+				Expect(err).NotTo(HaveOccurred())
+				resource := &batchv1.OSIndexPolicy{}
+				Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+				return resource.ResourceVersion
+			}
+
+			By("creating the policy, then recording the managed indices")
+			reconcileOnce()
+			steady := reconcileOnce()
+			Expect(statusWrites).To(Equal(2))
+
+			By("reconciling again with nothing changed")
+			Expect(reconcileOnce()).To(Equal(steady))
+			Expect(reconcileOnce()).To(Equal(steady))
+			Expect(statusWrites).To(Equal(2))
+		})
+	})
 })
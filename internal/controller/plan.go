@@ -0,0 +1,74 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	batchv1 "github.com/a8uhnf/opensearch-ism-crd/api/v1"
+)
+
+const (
+	eventPlanned = "Planned"
+	// maxPlanDiffFields bounds the changed fields listed in the plan status.
+	maxPlanDiffFields = 50
+)
+
+// planning reports whether the reconciler only plans its changes to the
+// policy, with the --dry-run flag or spec.plan.
+func (r *OSIndexPolicyReconciler) planning(policy batchv1.IndexPolicyObject) bool {
+	return r.DryRun || policy.GetSpec().Plan
+}
+
+// recordPlan reports the operation the reconciler would make in status, and in
+// an Event when the plan changed.
+func (r *OSIndexPolicyReconciler) recordPlan(ctx context.Context, policy batchv1.IndexPolicyObject, operation string, diff []string) {
+	diff = diff[:min(len(diff), maxPlanDiffFields)]
+	previous := policy.GetStatus().Plan
+	policy.GetStatus().Plan = &batchv1.PlanStatus{Operation: operation, Diff: diff, PlanTime: metav1.Now()}
+	if operation == batchv1.PlanNoOp {
+		if !isSyncedAtGeneration(policy) {
+			setSynced(policy, metav1.ConditionTrue, "InSync", "Index policy in OpenSearch matches the spec")
+		}
+	} else {
+		setSynced(policy, metav1.ConditionFalse, "Planned", planMessage(policy.GetSpec().PolicyID, operation, diff))
+	}
+	if previous != nil && previous.Operation == operation && slices.Equal(previous.Diff, diff) {
+		// Keep the status as it is, so the unchanged plan is not written again.
+		policy.GetStatus().Plan.PlanTime = previous.PlanTime
+		return
+	}
+	logf.FromContext(ctx).Info("Planned index policy change, not applying it", "policyName", policy.GetName(), "operation", operation, "fields", diff)
+	r.Recorder.Event(policy, corev1.EventTypeNormal, eventPlanned, planMessage(policy.GetSpec().PolicyID, operation, diff))
+}
+
+// planMessage describes a planned operation.
+func planMessage(policyID, operation string, diff []string) string {
+	switch operation {
+	case batchv1.PlanNoOp:
+		return fmt.Sprintf("Plan: index policy %s is up to date", policyID)
+	case batchv1.PlanUpdate:
+		return fmt.Sprintf("Plan: update index policy %s, %s", policyID, diffSummary(diff))
+	}
+	return fmt.Sprintf("Plan: %s index policy %s", operation, policyID)
+}
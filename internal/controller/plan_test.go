@@ -0,0 +1,93 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	batchv1 "github.com/a8uhnf/opensearch-ism-crd/api/v1"
+	"github.com/a8uhnf/opensearch-ism-crd/internal/pkg/opensearch"
+)
+
+// readOnlyClient is an OpenSearch client that fails the test on any write.
+type readOnlyClient struct {
+	opensearch.OpenSearch
+}
+
+func (readOnlyClient) UpdateIndexPolicy(_ context.Context, _ string, _, _ int64, _ *batchv1.OpensearchIndexPolicy) (*opensearch.IndexPolicy, error) {
+	Fail("UpdateIndexPolicy called in plan mode")
+	return nil, nil
+}
+
+var _ = Describe("Plan mode", func() {
+	var (
+		reconciler *OSIndexPolicyReconciler
+		recorder   *record.FakeRecorder
+		policy     *batchv1.OSIndexPolicy
+		remote     *opensearch.IndexPolicy
+	)
+
+	BeforeEach(func() {
+		recorder = record.NewFakeRecorder(10)
+		reconciler = &OSIndexPolicyReconciler{Recorder: recorder}
+		policy = &batchv1.OSIndexPolicy{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "logs"},
+			Spec: batchv1.OSIndexPolicySpec{
+				PolicyID: "logs",
+				Plan:     true,
+				Policy:   batchv1.OpensearchIndexPolicy{Description: "logs", DefaultState: "hot"},
+			},
+		}
+		raw, err := json.Marshal(opensearch.WithOwner(&batchv1.OpensearchIndexPolicy{Description: "logs", DefaultState: "warm"}, "default/logs"))
+		Expect(err).NotTo(HaveOccurred())
+		remote = &opensearch.IndexPolicy{ID: "logs", Policy: raw}
+	})
+
+	It("records the planned update without making it", func() {
+		Expect(reconciler.syncPolicy(context.Background(), readOnlyClient{}, policy, remote, false)).To(Succeed())
+
+		Expect(policy.Status.Plan.Operation).To(Equal(batchv1.PlanUpdate))
+		Expect(policy.Status.Plan.Diff).To(Equal([]string{"default_state"}))
+		Expect(meta.FindStatusCondition(policy.Status.Conditions, batchv1.ConditionSynced).Reason).To(Equal("Planned"))
+		Expect(recorder.Events).To(Receive(ContainSubstring("Plan: update index policy logs")))
+	})
+
+	It("plans every object with --dry-run and emits an Event only when the plan changes", func() {
+		policy.Spec.Plan = false
+		reconciler.DryRun = true
+		Expect(reconciler.syncPolicy(context.Background(), readOnlyClient{}, policy, remote, false)).To(Succeed())
+		Expect(reconciler.syncPolicy(context.Background(), readOnlyClient{}, policy, remote, false)).To(Succeed())
+
+		Expect(policy.Status.Plan.Operation).To(Equal(batchv1.PlanUpdate))
+		Expect(recorder.Events).To(HaveLen(1))
+	})
+
+	It("reports a no-op when the policy is up to date", func() {
+		policy.Spec.Policy.DefaultState = "warm"
+		Expect(reconciler.syncPolicy(context.Background(), readOnlyClient{}, policy, remote, false)).To(Succeed())
+
+		Expect(policy.Status.Plan.Operation).To(Equal(batchv1.PlanNoOp))
+		Expect(meta.IsStatusConditionTrue(policy.Status.Conditions, batchv1.ConditionSynced)).To(BeTrue())
+	})
+})
@@ -0,0 +1,241 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	batchv1 "github.com/a8uhnf/opensearch-ism-crd/api/v1"
+	"github.com/a8uhnf/opensearch-ism-crd/internal/pkg/render"
+)
+
+const (
+	// templateRefIndex indexes OSIndexPolicies and ClusterOSIndexPolicies by the
+	// OSIndexPolicyTemplate they render.
+	templateRefIndex = ".spec.template_ref.name"
+	// baseIndex indexes OSIndexPolicies and ClusterOSIndexPolicies by their base.
+	baseIndex = ".spec.base.name"
+	// policyConfigMapIndex indexes OSIndexPolicies and ClusterOSIndexPolicies by
+	// the ConfigMap their policy is read from.
+	policyConfigMapIndex = ".spec.policy_from.config_map_key_ref.name"
+)
+
+// +kubebuilder:rbac:groups=batch.a8uhnf.com,resources=osindexpolicytemplates,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch
+
+// resolvePolicy replaces spec.policy in memory with the policy rendered from
+// spec.template_ref, read from spec.policy_from or merged over spec.base. Like the namespace prefix, it runs
+// before the copy status patches are computed against, so the stored spec is
+// never written.
+func (r *OSIndexPolicyReconciler) resolvePolicy(ctx context.Context, policy batchv1.IndexPolicyObject) error {
+	spec := policy.GetSpec()
+	if !render.Composed(spec) {
+		return nil
+	}
+	resolved, err := render.Resolve(ctx, r, policy, r.referenceNamespace(policy))
+	if err != nil {
+		return err
+	}
+	spec.Policy = *resolved
+	return nil
+}
+
+// failResolve records that the policy could not be resolved, leaving OpenSearch
+// untouched, and returns the error to retry with backoff.
+func (r *OSIndexPolicyReconciler) failResolve(ctx context.Context, policy, original batchv1.IndexPolicyObject, resolveErr error) (ctrl.Result, error) {
+	logr := logf.FromContext(ctx)
+	logr.Error(resolveErr, "Failed to resolve index policy", "policyName", policy.GetName())
+	setSynced(policy, metav1.ConditionFalse, "PolicyUnresolved", resolveErr.Error())
+	r.Recorder.Eventf(policy, corev1.EventTypeWarning, eventSyncFailed, "Failed to resolve index policy: %v", resolveErr)
+	if err := r.patchStatus(ctx, policy, original); err != nil {
+		logr.Error(err, "Failed to patch OSIndexPolicy status")
+	}
+	return ctrl.Result{}, resolveErr
+}
+
+// templateRefName returns the name of the template the spec renders, for templateRefIndex.
+func templateRefName(spec *batchv1.OSIndexPolicySpec) []string {
+	if spec.TemplateRef == nil {
+		return nil
+	}
+	return []string{spec.TemplateRef.Name}
+}
+
+// policiesForTemplate requeues every OSIndexPolicy rendering the template, and
+// those inheriting from them, to render them again.
+func (r *OSIndexPolicyReconciler) policiesForTemplate(ctx context.Context, obj client.Object) []reconcile.Request {
+	policies := &batchv1.OSIndexPolicyList{}
+	if err := r.List(ctx, policies,
+		client.InNamespace(obj.GetNamespace()),
+		client.MatchingFields{templateRefIndex: obj.GetName()},
+	); err != nil {
+		return nil
+	}
+	requests := make([]reconcile.Request, 0, len(policies.Items))
+	for _, p := range policies.Items {
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Namespace: p.Namespace, Name: p.Name},
+		})
+		requests = append(requests, r.policiesForBase(ctx, &p)...)
+	}
+	return requests
+}
+
+// clusterPoliciesForTemplate requeues every ClusterOSIndexPolicy rendering the
+// template, and those inheriting from them, when it lives in ClusterSecretNamespace.
+func (r *OSIndexPolicyReconciler) clusterPoliciesForTemplate(ctx context.Context, obj client.Object) []reconcile.Request {
+	if r.ClusterSecretNamespace == "" || obj.GetNamespace() != r.ClusterSecretNamespace {
+		return nil
+	}
+	policies := &batchv1.ClusterOSIndexPolicyList{}
+	if err := r.List(ctx, policies, client.MatchingFields{templateRefIndex: obj.GetName()}); err != nil {
+		return nil
+	}
+	requests := make([]reconcile.Request, 0, len(policies.Items))
+	for _, p := range policies.Items {
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Name: p.Name},
+		})
+		requests = append(requests, r.clusterPoliciesForBase(ctx, &p)...)
+	}
+	return requests
+}
+
+// policyConfigMapName returns the name of the ConfigMap the policy of the spec
+// is read from, for policyConfigMapIndex.
+func policyConfigMapName(spec *batchv1.OSIndexPolicySpec) []string {
+	if spec.PolicyFrom == nil {
+		return nil
+	}
+	return []string{spec.PolicyFrom.ConfigMapKeyRef.Name}
+}
+
+// policiesForConfigMap requeues every OSIndexPolicy reading its policy from the
+// ConfigMap, and those inheriting from them.
+func (r *OSIndexPolicyReconciler) policiesForConfigMap(ctx context.Context, obj client.Object) []reconcile.Request {
+	policies := &batchv1.OSIndexPolicyList{}
+	if err := r.List(ctx, policies,
+		client.InNamespace(obj.GetNamespace()),
+		client.MatchingFields{policyConfigMapIndex: obj.GetName()},
+	); err != nil {
+		return nil
+	}
+	requests := make([]reconcile.Request, 0, len(policies.Items))
+	for _, p := range policies.Items {
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Namespace: p.Namespace, Name: p.Name},
+		})
+		requests = append(requests, r.policiesForBase(ctx, &p)...)
+	}
+	return requests
+}
+
+// clusterPoliciesForConfigMap requeues every ClusterOSIndexPolicy reading its
+// policy from the ConfigMap, and those inheriting from them, when it lives in
+// ClusterSecretNamespace.
+func (r *OSIndexPolicyReconciler) clusterPoliciesForConfigMap(ctx context.Context, obj client.Object) []reconcile.Request {
+	if r.ClusterSecretNamespace == "" || obj.GetNamespace() != r.ClusterSecretNamespace {
+		return nil
+	}
+	policies := &batchv1.ClusterOSIndexPolicyList{}
+	if err := r.List(ctx, policies, client.MatchingFields{policyConfigMapIndex: obj.GetName()}); err != nil {
+		return nil
+	}
+	requests := make([]reconcile.Request, 0, len(policies.Items))
+	for _, p := range policies.Items {
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Name: p.Name},
+		})
+		requests = append(requests, r.clusterPoliciesForBase(ctx, &p)...)
+	}
+	return requests
+}
+
+// baseName returns the name of the base of the spec, for baseIndex.
+func baseName(spec *batchv1.OSIndexPolicySpec) []string {
+	if spec.Base == nil {
+		return nil
+	}
+	return []string{spec.Base.Name}
+}
+
+// policiesForBase requeues the OSIndexPolicies inheriting from the policy,
+// directly or through other bases.
+func (r *OSIndexPolicyReconciler) policiesForBase(ctx context.Context, obj client.Object) []reconcile.Request {
+	return inheriting(obj, func(name string) ([]types.NamespacedName, error) {
+		policies := &batchv1.OSIndexPolicyList{}
+		if err := r.List(ctx, policies,
+			client.InNamespace(obj.GetNamespace()),
+			client.MatchingFields{baseIndex: name},
+		); err != nil {
+			return nil, err
+		}
+		keys := make([]types.NamespacedName, 0, len(policies.Items))
+		for _, p := range policies.Items {
+			keys = append(keys, types.NamespacedName{Namespace: p.Namespace, Name: p.Name})
+		}
+		return keys, nil
+	})
+}
+
+// clusterPoliciesForBase requeues the ClusterOSIndexPolicies inheriting from
+// the policy, directly or through other bases.
+func (r *OSIndexPolicyReconciler) clusterPoliciesForBase(ctx context.Context, obj client.Object) []reconcile.Request {
+	return inheriting(obj, func(name string) ([]types.NamespacedName, error) {
+		policies := &batchv1.ClusterOSIndexPolicyList{}
+		if err := r.List(ctx, policies, client.MatchingFields{baseIndex: name}); err != nil {
+			return nil, err
+		}
+		keys := make([]types.NamespacedName, 0, len(policies.Items))
+		for _, p := range policies.Items {
+			keys = append(keys, types.NamespacedName{Name: p.Name})
+		}
+		return keys, nil
+	})
+}
+
+// inheriting walks the objects inheriting from obj, listing the direct heirs
+// of a name with heirs.
+func inheriting(obj client.Object, heirs func(name string) ([]types.NamespacedName, error)) []reconcile.Request {
+	var requests []reconcile.Request
+	seen := map[string]bool{obj.GetName(): true}
+	queue := []string{obj.GetName()}
+	for len(queue) > 0 {
+		keys, err := heirs(queue[0])
+		queue = queue[1:]
+		if err != nil {
+			return requests
+		}
+		for _, key := range keys {
+			if seen[key.Name] {
+				continue
+			}
+			seen[key.Name] = true
+			queue = append(queue, key.Name)
+			requests = append(requests, reconcile.Request{NamespacedName: key})
+		}
+	}
+	return requests
+}
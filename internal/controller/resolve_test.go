@@ -0,0 +1,326 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	batchv1 "github.com/a8uhnf/opensearch-ism-crd/api/v1"
+	"github.com/a8uhnf/opensearch-ism-crd/internal/pkg/opensearch"
+)
+
+var _ = Describe("Policy templates", func() {
+	var (
+		reconciler *OSIndexPolicyReconciler
+		template   *batchv1.OSIndexPolicyTemplate
+		key        types.NamespacedName
+	)
+
+	BeforeEach(func() {
+		key = types.NamespacedName{Namespace: "team-a", Name: "logs"}
+		template = &batchv1.OSIndexPolicyTemplate{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "retention"},
+			Spec: batchv1.OSIndexPolicyTemplateSpec{
+				Parameters: []batchv1.TemplateParameter{{Name: "index_pattern"}},
+				Policy: batchv1.OpensearchIndexPolicy{
+					ISMTemplate: &batchv1.ISMTemplate{IndexPatterns: []string{"${index_pattern}"}},
+				},
+			},
+		}
+		policy := &batchv1.OSIndexPolicy{
+			ObjectMeta: metav1.ObjectMeta{Namespace: key.Namespace, Name: key.Name},
+			Spec: batchv1.OSIndexPolicySpec{
+				PolicyID: "logs",
+				TemplateRef: &batchv1.PolicyTemplateRef{
+					Name:       "retention",
+					Parameters: map[string]string{"index_pattern": "logs-*"},
+				},
+				OpensearhConnection: batchv1.OpensearhConnection{URL: "http://opensearch.invalid:9200"},
+			},
+		}
+		// The guardrail stops the reconcile before it reaches OpenSearch.
+		guardrail := &batchv1.OSIndexPolicyGuardrail{
+			ObjectMeta: metav1.ObjectMeta{Name: "team-a"},
+			Spec: batchv1.OSIndexPolicyGuardrailSpec{
+				Namespaces:      []string{"team-a"},
+				AllowedClusters: []string{"https://logs.example.com:9200"},
+			},
+		}
+		scheme := runtime.NewScheme()
+		Expect(batchv1.AddToScheme(scheme)).To(Succeed())
+		reconciler = &OSIndexPolicyReconciler{
+			Client: fake.NewClientBuilder().WithScheme(scheme).
+				WithObjects(policy, template, guardrail).
+				WithStatusSubresource(policy).
+				WithIndex(&batchv1.OSIndexPolicy{}, templateRefIndex, func(obj client.Object) []string {
+					return templateRefName(&obj.(*batchv1.OSIndexPolicy).Spec)
+				}).
+				WithIndex(&batchv1.OSIndexPolicy{}, baseIndex, func(obj client.Object) []string {
+					return baseName(&obj.(*batchv1.OSIndexPolicy).Spec)
+				}).
+				Build(),
+			Recorder: record.NewFakeRecorder(10),
+		}
+	})
+
+	It("renders the policy from the template and records its hash", func() {
+		_, err := reconciler.Reconcile(context.Background(), reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+
+		rendered := &batchv1.OSIndexPolicy{}
+		Expect(reconciler.Get(context.Background(), key, rendered)).To(Succeed())
+		Expect(rendered.Status.EffectiveIndexPatterns).To(Equal([]string{"logs-*"}))
+		Expect(rendered.Status.PolicyHash).NotTo(BeEmpty())
+		Expect(rendered.Spec.Policy.ISMTemplate).To(BeNil())
+	})
+
+	It("reports policies the template cannot render", func() {
+		Expect(reconciler.Delete(context.Background(), template)).To(Succeed())
+		_, err := reconciler.Reconcile(context.Background(), reconcile.Request{NamespacedName: key})
+		Expect(err).To(HaveOccurred())
+
+		unresolved := &batchv1.OSIndexPolicy{}
+		Expect(reconciler.Get(context.Background(), key, unresolved)).To(Succeed())
+		synced := meta.FindStatusCondition(unresolved.Status.Conditions, batchv1.ConditionSynced)
+		Expect(synced).NotTo(BeNil())
+		Expect(synced.Reason).To(Equal("PolicyUnresolved"))
+	})
+
+	It("requeues the policies rendering a template", func() {
+		Expect(reconciler.policiesForTemplate(context.Background(), template)).To(ConsistOf(reconcile.Request{NamespacedName: key}))
+	})
+
+	It("reports a changed rendering as an update rather than drift", func() {
+		policy := &batchv1.OSIndexPolicy{
+			ObjectMeta: metav1.ObjectMeta{Namespace: key.Namespace, Name: key.Name, Generation: 1},
+			Spec: batchv1.OSIndexPolicySpec{
+				PolicyID: "logs",
+				Policy:   batchv1.OpensearchIndexPolicy{Description: "rendered", DefaultState: "hot"},
+			},
+		}
+		remotePolicy := func(policy *batchv1.OpensearchIndexPolicy) *opensearch.IndexPolicy {
+			raw, err := json.Marshal(opensearch.WithOwner(policy, "team-a/logs"))
+			Expect(err).NotTo(HaveOccurred())
+			return &opensearch.IndexPolicy{ID: "logs", SeqNo: 1, PrimaryTerm: 1, Policy: raw}
+		}
+		synced := remotePolicy(policy.Spec.Policy.DeepCopy())
+		client := &updateRecorder{}
+		Expect(reconciler.syncPolicy(context.Background(), client, policy, synced, false)).To(Succeed())
+		Expect(client.updates).To(BeZero())
+
+		By("rendering the template again without a new generation")
+		policy.Spec.Policy.DefaultState = "warm"
+		Expect(reconciler.syncPolicy(context.Background(), client, policy, synced, false)).To(Succeed())
+		Expect(client.updates).To(Equal(1))
+		Expect(meta.FindStatusCondition(policy.Status.Conditions, batchv1.ConditionSynced).Reason).To(Equal(eventUpdated))
+
+		By("finding the policy changed in OpenSearch")
+		Expect(reconciler.syncPolicy(context.Background(), client, policy, synced, false)).To(Succeed())
+		Expect(client.updates).To(Equal(2))
+		Expect(meta.FindStatusCondition(policy.Status.Conditions, batchv1.ConditionSynced).Reason).To(Equal(eventDriftCorrected))
+	})
+})
+
+var _ = Describe("Policy bases", func() {
+	var (
+		reconciler *OSIndexPolicyReconciler
+		base       *batchv1.OSIndexPolicy
+		key        types.NamespacedName
+	)
+
+	BeforeEach(func() {
+		key = types.NamespacedName{Namespace: "team-a", Name: "audit"}
+		connection := batchv1.OpensearhConnection{URL: "http://opensearch.invalid:9200"}
+		base = &batchv1.OSIndexPolicy{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "logs"},
+			Spec: batchv1.OSIndexPolicySpec{
+				PolicyID: "logs",
+				Policy: batchv1.OpensearchIndexPolicy{
+					DefaultState: "hot",
+					States: []*batchv1.State{
+						{Name: "hot", Transitions: []*batchv1.Transition{{StateName: "delete", Conditions: map[string]string{"min_index_age": "30d"}}}},
+						{Name: "delete", Actions: []*batchv1.Action{{Delete: &batchv1.DeleteAction{}}}},
+					},
+					ISMTemplate: &batchv1.ISMTemplate{IndexPatterns: []string{"logs-*"}},
+				},
+				OpensearhConnection: connection,
+			},
+		}
+		policy := &batchv1.OSIndexPolicy{
+			ObjectMeta: metav1.ObjectMeta{Namespace: key.Namespace, Name: key.Name},
+			Spec: batchv1.OSIndexPolicySpec{
+				PolicyID: "audit",
+				Base:     &batchv1.PolicyBaseRef{Name: "logs"},
+				Policy: batchv1.OpensearchIndexPolicy{
+					ISMTemplate: &batchv1.ISMTemplate{IndexPatterns: []string{"audit-*"}},
+				},
+				OpensearhConnection: connection,
+			},
+		}
+		chained := &batchv1.OSIndexPolicy{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "audit-eu"},
+			Spec: batchv1.OSIndexPolicySpec{
+				PolicyID:            "audit-eu",
+				Base:                &batchv1.PolicyBaseRef{Name: "audit"},
+				OpensearhConnection: connection,
+			},
+		}
+		// The guardrail stops the reconcile before it reaches OpenSearch.
+		guardrail := &batchv1.OSIndexPolicyGuardrail{
+			ObjectMeta: metav1.ObjectMeta{Name: "team-a"},
+			Spec: batchv1.OSIndexPolicyGuardrailSpec{
+				Namespaces:      []string{"team-a"},
+				AllowedClusters: []string{"https://logs.example.com:9200"},
+			},
+		}
+		scheme := runtime.NewScheme()
+		Expect(batchv1.AddToScheme(scheme)).To(Succeed())
+		reconciler = &OSIndexPolicyReconciler{
+			Client: fake.NewClientBuilder().WithScheme(scheme).
+				WithObjects(base, policy, chained, guardrail).
+				WithStatusSubresource(base, policy, chained).
+				WithIndex(&batchv1.OSIndexPolicy{}, baseIndex, func(obj client.Object) []string {
+					return baseName(&obj.(*batchv1.OSIndexPolicy).Spec)
+				}).
+				Build(),
+			Recorder: record.NewFakeRecorder(10),
+		}
+	})
+
+	It("records the policy resolved from the base in status", func() {
+		_, err := reconciler.Reconcile(context.Background(), reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+
+		resolved := &batchv1.OSIndexPolicy{}
+		Expect(reconciler.Get(context.Background(), key, resolved)).To(Succeed())
+		Expect(resolved.Status.ResolvedPolicy).NotTo(BeNil())
+		Expect(resolved.Status.ResolvedPolicy.States).To(HaveLen(2))
+		Expect(resolved.Status.ResolvedPolicy.ISMTemplate.IndexPatterns).To(Equal([]string{"audit-*"}))
+		Expect(resolved.Status.EffectiveIndexPatterns).To(Equal([]string{"audit-*"}))
+		Expect(resolved.Spec.Policy.States).To(BeEmpty())
+	})
+
+	It("does not record the policy of a policy without a base", func() {
+		_, err := reconciler.Reconcile(context.Background(), reconcile.Request{NamespacedName: client.ObjectKeyFromObject(base)})
+		Expect(err).NotTo(HaveOccurred())
+
+		plain := &batchv1.OSIndexPolicy{}
+		Expect(reconciler.Get(context.Background(), client.ObjectKeyFromObject(base), plain)).To(Succeed())
+		Expect(plain.Status.ResolvedPolicy).To(BeNil())
+	})
+
+	It("requeues the policies inheriting from a base, directly or not", func() {
+		Expect(reconciler.policiesForBase(context.Background(), base)).To(ConsistOf(
+			reconcile.Request{NamespacedName: key},
+			reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "team-a", Name: "audit-eu"}},
+		))
+	})
+})
+
+var _ = Describe("Policy sources", func() {
+	var (
+		reconciler *OSIndexPolicyReconciler
+		configMap  *corev1.ConfigMap
+		key        types.NamespacedName
+	)
+
+	BeforeEach(func() {
+		key = types.NamespacedName{Namespace: "team-a", Name: "logs"}
+		configMap = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "ism-policies"},
+			Data: map[string]string{
+				"logs.json": `{"policy": {"default_state": "hot", "states": [{"name": "hot"}], "ism_template": [{"index_patterns": ["logs-*"]}]}}`,
+			},
+		}
+		policy := &batchv1.OSIndexPolicy{
+			ObjectMeta: metav1.ObjectMeta{Namespace: key.Namespace, Name: key.Name},
+			Spec: batchv1.OSIndexPolicySpec{
+				PolicyID: "logs",
+				PolicyFrom: &batchv1.PolicySource{ConfigMapKeyRef: corev1.ConfigMapKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "ism-policies"},
+					Key:                  "logs.json",
+				}},
+				OpensearhConnection: batchv1.OpensearhConnection{URL: "http://opensearch.invalid:9200"},
+			},
+		}
+		// The guardrail stops the reconcile before it reaches OpenSearch.
+		guardrail := &batchv1.OSIndexPolicyGuardrail{
+			ObjectMeta: metav1.ObjectMeta{Name: "team-a"},
+			Spec: batchv1.OSIndexPolicyGuardrailSpec{
+				Namespaces:      []string{"team-a"},
+				AllowedClusters: []string{"https://logs.example.com:9200"},
+			},
+		}
+		scheme := runtime.NewScheme()
+		Expect(batchv1.AddToScheme(scheme)).To(Succeed())
+		Expect(corev1.AddToScheme(scheme)).To(Succeed())
+		reconciler = &OSIndexPolicyReconciler{
+			Client: fake.NewClientBuilder().WithScheme(scheme).
+				WithObjects(policy, configMap, guardrail).
+				WithStatusSubresource(policy).
+				WithIndex(&batchv1.OSIndexPolicy{}, policyConfigMapIndex, func(obj client.Object) []string {
+					return policyConfigMapName(&obj.(*batchv1.OSIndexPolicy).Spec)
+				}).
+				WithIndex(&batchv1.OSIndexPolicy{}, baseIndex, func(obj client.Object) []string {
+					return baseName(&obj.(*batchv1.OSIndexPolicy).Spec)
+				}).
+				Build(),
+			Recorder: record.NewFakeRecorder(10),
+		}
+	})
+
+	It("reads the policy from the ConfigMap", func() {
+		_, err := reconciler.Reconcile(context.Background(), reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+
+		read := &batchv1.OSIndexPolicy{}
+		Expect(reconciler.Get(context.Background(), key, read)).To(Succeed())
+		Expect(read.Status.ResolvedPolicy).NotTo(BeNil())
+		Expect(read.Status.ResolvedPolicy.DefaultState).To(Equal("hot"))
+		Expect(read.Status.EffectiveIndexPatterns).To(Equal([]string{"logs-*"}))
+	})
+
+	It("reports ConfigMaps without a valid policy", func() {
+		configMap.Data["logs.json"] = `{"default_state": "hot", "states": [{"name": "hot", "unknown": true}]}`
+		Expect(reconciler.Update(context.Background(), configMap)).To(Succeed())
+		_, err := reconciler.Reconcile(context.Background(), reconcile.Request{NamespacedName: key})
+		Expect(err).To(MatchError(ContainSubstring("unsupported policy fields states[0].unknown")))
+
+		unresolved := &batchv1.OSIndexPolicy{}
+		Expect(reconciler.Get(context.Background(), key, unresolved)).To(Succeed())
+		synced := meta.FindStatusCondition(unresolved.Status.Conditions, batchv1.ConditionSynced)
+		Expect(synced).NotTo(BeNil())
+		Expect(synced.Reason).To(Equal("PolicyUnresolved"))
+	})
+
+	It("requeues the policies reading the ConfigMap", func() {
+		Expect(reconciler.policiesForConfigMap(context.Background(), configMap)).To(ConsistOf(reconcile.Request{NamespacedName: key}))
+	})
+})
@@ -0,0 +1,41 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	batchv1 "github.com/a8uhnf/opensearch-ism-crd/api/v1"
+)
+
+var _ = Describe("Periodic resync", func() {
+	DescribeTable("requeues the policy for its next drift check",
+		func(managerInterval time.Duration, policyInterval *metav1.Duration, want time.Duration) {
+			reconciler := &OSIndexPolicyReconciler{ResyncInterval: managerInterval}
+			policy := &batchv1.OSIndexPolicy{Spec: batchv1.OSIndexPolicySpec{ResyncInterval: policyInterval}}
+
+			Expect(reconciler.resyncAfter(policy).RequeueAfter).To(Equal(want))
+		},
+		Entry("default", time.Duration(0), nil, defaultResyncInterval),
+		Entry("manager flag", 10*time.Minute, nil, 10*time.Minute),
+		Entry("policy override", 10*time.Minute, &metav1.Duration{Duration: time.Hour}, time.Hour),
+	)
+})
@@ -0,0 +1,154 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	batchv1 "github.com/a8uhnf/opensearch-ism-crd/api/v1"
+	"github.com/a8uhnf/opensearch-ism-crd/internal/pkg/opensearch"
+)
+
+const (
+	eventRolloutStarted = "RolloutStarted"
+	eventRolloutFailed  = "RolloutFailed"
+)
+
+// startRollout records the rollout of the policy version just stored in
+// OpenSearch, as spec.rollout asks, for resumeRollout to carry out. It replaces
+// a rollout of an older version that is still pending.
+func startRollout(policy batchv1.IndexPolicyObject, seqNo int64) {
+	spec := policy.GetSpec().Rollout
+	if !rolloutEnabled(spec) {
+		policy.GetStatus().Rollout = nil
+		return
+	}
+	policy.GetStatus().Rollout = &batchv1.RolloutStatus{
+		Strategy:    spec.Strategy,
+		PolicySeqNo: seqNo,
+		Phase:       batchv1.RolloutPhasePending,
+	}
+}
+
+// resumeRollout switches the managed indices to the policy version of a
+// pending rollout, picking up where the previous reconcile stopped. The
+// remaining indices stay in status.rollout when it fails, and the error
+// requeues the policy. Progress is then tracked by the explain sweep.
+func (r *OSIndexPolicyReconciler) resumeRollout(ctx context.Context, opensearchClient opensearch.OpenSearch, policy batchv1.IndexPolicyObject) error {
+	status := policy.GetStatus().Rollout
+	if status == nil || (status.Phase != batchv1.RolloutPhasePending && status.Phase != batchv1.RolloutPhaseChanging) {
+		return nil
+	}
+	spec := policy.GetSpec().Rollout
+	if !rolloutEnabled(spec) {
+		policy.GetStatus().Rollout = nil
+		return nil
+	}
+	logr := logf.FromContext(ctx)
+
+	if status.Phase == batchv1.RolloutPhasePending {
+		indices, err := opensearchClient.ExplainPolicy(ctx, policy.GetSpec().PolicyID, managedIndexPatterns(policy.GetSpec()))
+		if err != nil {
+			logr.Error(err, "Failed to list managed indices for rollout", "policyName", policy.GetName())
+			r.Recorder.Eventf(policy, corev1.EventTypeWarning, eventRolloutFailed, "Failed to list managed indices: %v", err)
+			return err
+		}
+		// Indices already running the version, e.g. attached since the update, are left alone.
+		status.Total = len(indices)
+		status.PendingIndices = nil
+		for _, index := range indices {
+			if index.PolicySeqNo < status.PolicySeqNo {
+				status.PendingIndices = append(status.PendingIndices, index.Index)
+			}
+		}
+		status.Phase = batchv1.RolloutPhaseChanging
+	}
+	if len(status.PendingIndices) == 0 {
+		status.Phase = batchv1.RolloutPhaseRequested
+		return nil
+	}
+
+	// change_policy is idempotent, so a partly applied set of changes is sent again in full.
+	if status.StartTime.IsZero() {
+		status.StartTime = metav1.Now()
+	}
+	var (
+		requested int
+		failures  []opensearch.ChangePolicyFailure
+	)
+	for _, change := range rolloutChanges(policy.GetSpec().PolicyID, spec) {
+		result, err := opensearchClient.ChangePolicy(ctx, status.PendingIndices, change)
+		if err != nil {
+			logr.Error(err, "Failed to roll out index policy", "policyName", policy.GetName(), "pending", len(status.PendingIndices))
+			r.Recorder.Eventf(policy, corev1.EventTypeWarning, eventRolloutFailed, "Failed to change policy of managed indices: %v", err)
+			return err
+		}
+		requested += result.UpdatedIndices
+		failures = append(failures, result.FailedIndices...)
+	}
+	pending := len(status.PendingIndices)
+	status.Requested += requested
+	status.PendingIndices = nil
+	status.Phase = batchv1.RolloutPhaseRequested
+
+	sort.Slice(failures, func(i, j int) bool { return failures[i].Index < failures[j].Index })
+	status.FailedIndices = nil
+	for _, failure := range failures {
+		if len(status.FailedIndices) == maxFailedIndices {
+			break
+		}
+		status.FailedIndices = append(status.FailedIndices, batchv1.FailedIndex{Index: failure.Index, Message: failure.Reason})
+	}
+	if len(failures) > 0 {
+		r.Recorder.Eventf(policy, corev1.EventTypeWarning, eventRolloutFailed,
+			"change_policy rejected %d of %d managed indices", len(failures), pending)
+	}
+	r.Recorder.Eventf(policy, corev1.EventTypeNormal, eventRolloutStarted,
+		"Rolling out index policy %s (seq_no %d) to %d managed indices with %s", policy.GetSpec().PolicyID, status.PolicySeqNo, requested, spec.Strategy)
+	return nil
+}
+
+// rolloutEnabled reports whether spec.rollout switches the managed indices to updated policies.
+func rolloutEnabled(spec *batchv1.Rollout) bool {
+	return spec != nil && spec.Strategy != "" && spec.Strategy != batchv1.RolloutNewIndicesOnly
+}
+
+// rolloutChanges returns the change_policy calls of the rollout: a single one
+// for ChangePolicyAll, one per state mapping for ChangePolicyWithStateMapping.
+func rolloutChanges(policyID string, spec *batchv1.Rollout) []opensearch.ChangePolicyRequest {
+	if spec.Strategy == batchv1.RolloutChangePolicyWithStateMapping {
+		changes := make([]opensearch.ChangePolicyRequest, 0, len(spec.StateMappings))
+		for _, mapping := range spec.StateMappings {
+			changes = append(changes, opensearch.ChangePolicyRequest{
+				PolicyID: policyID,
+				State:    mapping.To,
+				Include:  []opensearch.ChangePolicyInclude{{State: mapping.From}},
+			})
+		}
+		return changes
+	}
+	change := opensearch.ChangePolicyRequest{PolicyID: policyID}
+	for _, state := range spec.Include {
+		change.Include = append(change.Include, opensearch.ChangePolicyInclude{State: state})
+	}
+	return []opensearch.ChangePolicyRequest{change}
+}
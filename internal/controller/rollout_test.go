@@ -0,0 +1,139 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/client-go/tools/record"
+
+	batchv1 "github.com/a8uhnf/opensearch-ism-crd/api/v1"
+	"github.com/a8uhnf/opensearch-ism-crd/internal/pkg/opensearch"
+)
+
+// changePolicyRecorder is an OpenSearch client that records change_policy calls.
+type changePolicyRecorder struct {
+	opensearch.OpenSearch
+	indices []opensearch.ManagedIndex
+	changes []opensearch.ChangePolicyRequest
+	changed [][]string
+	err     error
+}
+
+func (c *changePolicyRecorder) ExplainPolicy(_ context.Context, _ string, _ []string) ([]opensearch.ManagedIndex, error) {
+	return c.indices, nil
+}
+
+func (c *changePolicyRecorder) ChangePolicy(_ context.Context, indices []string, change opensearch.ChangePolicyRequest) (*opensearch.ChangePolicyResult, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	c.changes = append(c.changes, change)
+	c.changed = append(c.changed, indices)
+	return &opensearch.ChangePolicyResult{UpdatedIndices: len(indices)}, nil
+}
+
+var _ = Describe("Policy rollout", func() {
+	var (
+		reconciler *OSIndexPolicyReconciler
+		client     *changePolicyRecorder
+		policy     *batchv1.OSIndexPolicy
+	)
+
+	BeforeEach(func() {
+		reconciler = &OSIndexPolicyReconciler{Recorder: record.NewFakeRecorder(10)}
+		client = &changePolicyRecorder{indices: []opensearch.ManagedIndex{
+			{Index: "logs-000001", State: "hot"},
+			{Index: "logs-000002", State: "warm"},
+		}}
+		policy = &batchv1.OSIndexPolicy{Spec: batchv1.OSIndexPolicySpec{PolicyID: "logs"}}
+	})
+
+	It("leaves existing indices alone with NewIndicesOnly", func() {
+		policy.Spec.Rollout = &batchv1.Rollout{Strategy: batchv1.RolloutNewIndicesOnly}
+		startRollout(policy, 8)
+		Expect(reconciler.resumeRollout(context.Background(), client, policy)).To(Succeed())
+
+		Expect(client.changes).To(BeEmpty())
+		Expect(policy.Status.Rollout).To(BeNil())
+	})
+
+	It("changes the policy of the indices in the included states", func() {
+		policy.Spec.Rollout = &batchv1.Rollout{Strategy: batchv1.RolloutChangePolicyAll, Include: []string{"hot"}}
+		startRollout(policy, 8)
+		Expect(reconciler.resumeRollout(context.Background(), client, policy)).To(Succeed())
+
+		Expect(client.changes).To(Equal([]opensearch.ChangePolicyRequest{
+			{PolicyID: "logs", Include: []opensearch.ChangePolicyInclude{{State: "hot"}}},
+		}))
+		Expect(policy.Status.Rollout.PolicySeqNo).To(Equal(int64(8)))
+		Expect(policy.Status.Rollout.Requested).To(Equal(2))
+		Expect(policy.Status.Rollout.Total).To(Equal(2))
+		Expect(policy.Status.Rollout.Phase).To(Equal(batchv1.RolloutPhaseRequested))
+	})
+
+	It("moves the indices to the mapped states", func() {
+		policy.Spec.Rollout = &batchv1.Rollout{
+			Strategy: batchv1.RolloutChangePolicyWithStateMapping,
+			StateMappings: []batchv1.StateMapping{
+				{From: "hot", To: "hot"},
+				{From: "warm", To: "cold"},
+			},
+		}
+		startRollout(policy, 8)
+		Expect(reconciler.resumeRollout(context.Background(), client, policy)).To(Succeed())
+
+		Expect(client.changes).To(Equal([]opensearch.ChangePolicyRequest{
+			{PolicyID: "logs", State: "hot", Include: []opensearch.ChangePolicyInclude{{State: "hot"}}},
+			{PolicyID: "logs", State: "cold", Include: []opensearch.ChangePolicyInclude{{State: "warm"}}},
+		}))
+	})
+	It("skips the indices already running the policy version", func() {
+		client.indices[1].PolicySeqNo = 8
+		policy.Spec.Rollout = &batchv1.Rollout{Strategy: batchv1.RolloutChangePolicyAll}
+		startRollout(policy, 8)
+		Expect(reconciler.resumeRollout(context.Background(), client, policy)).To(Succeed())
+
+		Expect(client.changed).To(Equal([][]string{{"logs-000001"}}))
+		Expect(policy.Status.Rollout.Total).To(Equal(2))
+		Expect(policy.Status.Rollout.Requested).To(Equal(1))
+	})
+
+	It("resumes the rollout after a failure", func() {
+		policy.Spec.Rollout = &batchv1.Rollout{Strategy: batchv1.RolloutChangePolicyAll}
+		startRollout(policy, 8)
+		client.err = errors.New("cluster_block_exception")
+		Expect(reconciler.resumeRollout(context.Background(), client, policy)).NotTo(Succeed())
+		Expect(policy.Status.Rollout.Phase).To(Equal(batchv1.RolloutPhaseChanging))
+		Expect(policy.Status.Rollout.PendingIndices).To(Equal([]string{"logs-000001", "logs-000002"}))
+
+		// The next reconcile sends the pending indices without listing them again.
+		client.err = nil
+		client.indices = nil
+		Expect(reconciler.resumeRollout(context.Background(), client, policy)).To(Succeed())
+		Expect(client.changed).To(Equal([][]string{{"logs-000001", "logs-000002"}}))
+		Expect(policy.Status.Rollout.Phase).To(Equal(batchv1.RolloutPhaseRequested))
+		Expect(policy.Status.Rollout.PendingIndices).To(BeEmpty())
+		Expect(policy.Status.Rollout.Requested).To(Equal(2))
+
+		Expect(reconciler.resumeRollout(context.Background(), client, policy)).To(Succeed())
+		Expect(client.changed).To(HaveLen(1))
+	})
+})
@@ -0,0 +1,289 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	batchv1 "github.com/a8uhnf/opensearch-ism-crd/api/v1"
+	"github.com/a8uhnf/opensearch-ism-crd/internal/metrics"
+	"github.com/a8uhnf/opensearch-ism-crd/internal/pkg/targets"
+)
+
+// +kubebuilder:rbac:groups=batch.a8uhnf.com,resources=opensearchclusters,verbs=get;list;watch
+
+// targetContextKey carries the name of the OpenSearchCluster being synced.
+type targetContextKey struct{}
+
+// withTarget marks the context of the sync of a policy to one of its targets.
+func withTarget(ctx context.Context, cluster string) context.Context {
+	return context.WithValue(ctx, targetContextKey{}, cluster)
+}
+
+// clientOwner names the policy as the owner of its cached OpenSearch client,
+// once per target: namespace/name@cluster.
+func clientOwner(ctx context.Context, policy client.Object) string {
+	if cluster, ok := ctx.Value(targetContextKey{}).(string); ok {
+		return targetOwner(policyKey(policy), cluster)
+	}
+	return policyKey(policy)
+}
+
+func targetOwner(key, cluster string) string {
+	return key + "@" + cluster
+}
+
+// releaseClients drops the cached clients of a deleted policy and of its targets.
+func (r *OSIndexPolicyReconciler) releaseClients(ctx context.Context, key string) {
+	r.Clients.Release(ctx, key)
+	r.Clients.ReleasePrefix(ctx, targetOwner(key, ""))
+}
+
+// syncTargets syncs the policy to every OpenSearchCluster of spec.targets in
+// turn, each on a copy of the policy holding the connection and the status of
+// the target, so that a failing cluster does not keep the others from syncing.
+// The outcome is recorded in status.targets and summed up in the conditions.
+func (r *OSIndexPolicyReconciler) syncTargets(ctx context.Context, policy batchv1.IndexPolicyObject, resync bool) (ctrl.Result, error) {
+	logr := logf.FromContext(ctx)
+	key := policyKey(policy)
+	status := policy.GetStatus()
+	// The targets are reported in status.targets only.
+	*status = batchv1.OSIndexPolicyStatus{
+		Conditions:             status.Conditions,
+		LastResyncAt:           status.LastResyncAt,
+		EffectivePolicyID:      status.EffectivePolicyID,
+		EffectiveIndexPatterns: status.EffectiveIndexPatterns,
+		PolicyHash:             status.PolicyHash,
+		ResolvedPolicy:         status.ResolvedPolicy,
+		Targets:                status.Targets,
+	}
+
+	clusters, missing, err := targets.Select(ctx, r, r.referenceNamespace(policy), policy.GetSpec().Targets)
+	if err != nil {
+		logr.Error(err, "Failed to select target clusters")
+		setSynced(policy, metav1.ConditionFalse, "TargetsUnresolved", err.Error())
+		return ctrl.Result{}, err
+	}
+
+	previous := map[string]batchv1.TargetStatus{}
+	for _, target := range status.Targets {
+		previous[target.Cluster] = target
+	}
+	var (
+		statuses []batchv1.TargetStatus
+		errs     []error
+		result   ctrl.Result
+		resynced = resync
+	)
+	for _, name := range missing {
+		target := batchv1.TargetStatus{Cluster: name}
+		meta.SetStatusCondition(&target.Conditions, metav1.Condition{
+			Type:               batchv1.ConditionSynced,
+			Status:             metav1.ConditionFalse,
+			Reason:             "ClusterNotFound",
+			Message:            fmt.Sprintf("OpenSearchCluster %s not found", name),
+			ObservedGeneration: policy.GetGeneration(),
+		})
+		statuses = append(statuses, target)
+		delete(previous, name)
+		resynced = false
+	}
+	for i := range clusters {
+		cluster := &clusters[i]
+		target := targetPolicy(policy, cluster, previous[cluster.Name])
+		delete(previous, cluster.Name)
+
+		targetResult, err := r.syncCluster(withTarget(ctx, cluster.Name), target, resync)
+		if err != nil {
+			logr.Error(err, "Failed to sync index policy to target", "cluster", cluster.Name)
+			errs = append(errs, fmt.Errorf("OpenSearchCluster %s: %w", cluster.Name, err))
+		}
+		if targetResult.RequeueAfter > 0 && (result.RequeueAfter == 0 || targetResult.RequeueAfter < result.RequeueAfter) {
+			result = targetResult
+		}
+		targetStatus := target.GetStatus()
+		metrics.SetSynced(key, cluster.Spec.OpensearhConnection.URL,
+			meta.IsStatusConditionTrue(targetStatus.Conditions, batchv1.ConditionSynced))
+		if targetStatus.LastResyncAt != policy.GetAnnotations()[batchv1.AnnotationResyncAt] {
+			resynced = false
+		}
+		statuses = append(statuses, batchv1.TargetStatus{
+			Cluster:             cluster.Name,
+			URL:                 cluster.Spec.OpensearhConnection.URL,
+			Conditions:          targetStatus.Conditions,
+			ClusterDistribution: targetStatus.ClusterDistribution,
+			ClusterVersion:      targetStatus.ClusterVersion,
+			ManagedIndices:      targetStatus.ManagedIndices,
+			Rollout:             targetStatus.Rollout,
+			RetryAttempts:       targetStatus.RetryAttempts,
+			AttachExisting:      targetStatus.AttachExisting,
+			Plan:                targetStatus.Plan,
+			SyncedPolicy:        targetStatus.SyncedPolicy,
+		})
+	}
+	// Clusters no longer targeted keep their ISM policy, but not a cached client.
+	for name := range previous {
+		r.Clients.Release(ctx, targetOwner(key, name))
+	}
+
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Cluster < statuses[j].Cluster })
+	status.Targets = statuses
+	if resynced {
+		status.LastResyncAt = policy.GetAnnotations()[batchv1.AnnotationResyncAt]
+	}
+	summarizeTargets(policy)
+
+	if len(errs) > 0 {
+		return ctrl.Result{}, kerrors.NewAggregate(errs)
+	}
+	if result.RequeueAfter == 0 {
+		result = r.resyncAfter(policy)
+	}
+	return result, nil
+}
+
+// targetPolicy returns a copy of the policy syncing to the cluster, with the
+// status recorded for the cluster by the previous reconcile.
+func targetPolicy(policy batchv1.IndexPolicyObject, cluster *batchv1.OpenSearchCluster, previous batchv1.TargetStatus) batchv1.IndexPolicyObject {
+	target := policy.DeepCopyObject().(batchv1.IndexPolicyObject)
+	spec := target.GetSpec()
+	spec.OpensearhConnection = *cluster.Spec.OpensearhConnection.DeepCopy()
+	spec.Targets = nil
+	previous = *previous.DeepCopy()
+	*target.GetStatus() = batchv1.OSIndexPolicyStatus{
+		Conditions:          previous.Conditions,
+		ClusterDistribution: previous.ClusterDistribution,
+		ClusterVersion:      previous.ClusterVersion,
+		ManagedIndices:      previous.ManagedIndices,
+		Rollout:             previous.Rollout,
+		RetryAttempts:       previous.RetryAttempts,
+		AttachExisting:      previous.AttachExisting,
+		Plan:                previous.Plan,
+		SyncedPolicy:        previous.SyncedPolicy,
+		LastResyncAt:        policy.GetStatus().LastResyncAt,
+	}
+	return target
+}
+
+// summarizeTargets sets the Synced and Reachable conditions of the policy from
+// those of its targets.
+func summarizeTargets(policy batchv1.IndexPolicyObject) {
+	status := policy.GetStatus()
+	if len(status.Targets) == 0 {
+		setSynced(policy, metav1.ConditionFalse, "NoTargets", "spec.targets selects no OpenSearchCluster")
+		meta.RemoveStatusCondition(&status.Conditions, batchv1.ConditionReachable)
+		return
+	}
+	var notSynced, unreachable, reachable []string
+	for _, target := range status.Targets {
+		if !meta.IsStatusConditionTrue(target.Conditions, batchv1.ConditionSynced) {
+			notSynced = append(notSynced, target.Cluster)
+		}
+		switch {
+		case meta.IsStatusConditionFalse(target.Conditions, batchv1.ConditionReachable):
+			unreachable = append(unreachable, target.Cluster)
+		case meta.IsStatusConditionTrue(target.Conditions, batchv1.ConditionReachable):
+			reachable = append(reachable, target.Cluster)
+		}
+	}
+	if len(notSynced) > 0 {
+		setSynced(policy, metav1.ConditionFalse, "TargetsNotSynced",
+			fmt.Sprintf("%d of %d targets not synced: %s", len(notSynced), len(status.Targets), strings.Join(notSynced, ", ")))
+	} else {
+		setSynced(policy, metav1.ConditionTrue, "TargetsSynced",
+			fmt.Sprintf("Index policy synced to %d targets", len(status.Targets)))
+	}
+	switch {
+	case len(unreachable) > 0:
+		setReachable(policy, metav1.ConditionFalse, "TargetsUnreachable",
+			fmt.Sprintf("%d of %d targets unreachable: %s", len(unreachable), len(status.Targets), strings.Join(unreachable, ", ")))
+	case len(reachable) > 0:
+		setReachable(policy, metav1.ConditionTrue, "Connected", "Every reached target answered")
+	default:
+		meta.RemoveStatusCondition(&status.Conditions, batchv1.ConditionReachable)
+	}
+}
+
+// policiesForCluster requeues every OSIndexPolicy of the namespace targeting
+// the OpenSearchCluster.
+func (r *OSIndexPolicyReconciler) policiesForCluster(ctx context.Context, obj client.Object) []reconcile.Request {
+	cluster, ok := obj.(*batchv1.OpenSearchCluster)
+	if !ok {
+		return nil
+	}
+	policies := &batchv1.OSIndexPolicyList{}
+	if err := r.List(ctx, policies, client.InNamespace(cluster.Namespace)); err != nil {
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, p := range policies.Items {
+		if targets.Selects(p.Spec.Targets, cluster) {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&p)})
+		}
+	}
+	return requests
+}
+
+// clusterPoliciesForCluster requeues every ClusterOSIndexPolicy targeting the
+// OpenSearchCluster, when it lives in ClusterSecretNamespace.
+func (r *OSIndexPolicyReconciler) clusterPoliciesForCluster(ctx context.Context, obj client.Object) []reconcile.Request {
+	cluster, ok := obj.(*batchv1.OpenSearchCluster)
+	if !ok || r.ClusterSecretNamespace == "" || cluster.Namespace != r.ClusterSecretNamespace {
+		return nil
+	}
+	policies := &batchv1.ClusterOSIndexPolicyList{}
+	if err := r.List(ctx, policies); err != nil {
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, p := range policies.Items {
+		if targets.Selects(p.Spec.Targets, cluster) {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&p)})
+		}
+	}
+	return requests
+}
+
+// clustersForSecret returns the OpenSearchClusters of the Secret's namespace
+// whose connection references it.
+func (r *OSIndexPolicyReconciler) clustersForSecret(ctx context.Context, secret client.Object) []batchv1.OpenSearchCluster {
+	clusters := &batchv1.OpenSearchClusterList{}
+	if err := r.List(ctx, clusters, client.InNamespace(secret.GetNamespace())); err != nil {
+		return nil
+	}
+	var referencing []batchv1.OpenSearchCluster
+	for _, cluster := range clusters.Items {
+		for _, name := range connectionSecretNames(cluster.Spec.OpensearhConnection) {
+			if name == secret.GetName() {
+				referencing = append(referencing, cluster)
+				break
+			}
+		}
+	}
+	return referencing
+}
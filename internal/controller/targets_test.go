@@ -0,0 +1,159 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	batchv1 "github.com/a8uhnf/opensearch-ism-crd/api/v1"
+	"github.com/a8uhnf/opensearch-ism-crd/internal/pkg/opensearch"
+)
+
+var _ = Describe("Policy targets", func() {
+	var (
+		server     *httptest.Server
+		reconciler *OSIndexPolicyReconciler
+		eu         *batchv1.OpenSearchCluster
+		key        = types.NamespacedName{Namespace: "team-a", Name: "logs"}
+	)
+
+	BeforeEach(func() {
+		var (
+			mu     sync.Mutex
+			stored json.RawMessage
+		)
+		// A minimal OpenSearch keeping the policy it is sent.
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case r.URL.Path == "/":
+				_, _ = w.Write([]byte(`{"version":{"distribution":"opensearch","number":"2.11.0"}}`))
+			case r.URL.Path == "/_plugins/_ism/explain":
+				_, _ = w.Write([]byte(`{"total_managed_indices":0}`))
+			case r.Method == http.MethodPut:
+				body, _ := io.ReadAll(r.Body)
+				request := struct {
+					Policy json.RawMessage `json:"policy"`
+				}{}
+				_ = json.Unmarshal(body, &request)
+				stored = request.Policy
+				_ = json.NewEncoder(w).Encode(map[string]interface{}{"_id": "logs", "_seq_no": 0, "_primary_term": 1, "policy": stored})
+			case stored == nil:
+				w.WriteHeader(http.StatusNotFound)
+			default:
+				_ = json.NewEncoder(w).Encode(map[string]interface{}{"_id": "logs", "_seq_no": 0, "_primary_term": 1, "policy": stored})
+			}
+		}))
+
+		eu = &batchv1.OpenSearchCluster{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "eu", Labels: map[string]string{"tier": "logs"}},
+			Spec:       batchv1.OpenSearchClusterSpec{OpensearhConnection: batchv1.OpensearhConnection{URL: server.URL}},
+		}
+		us := &batchv1.OpenSearchCluster{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "us"},
+			Spec:       batchv1.OpenSearchClusterSpec{OpensearhConnection: batchv1.OpensearhConnection{URL: "https://us.example.com:9200"}},
+		}
+		// The guardrail keeps the policy from reaching the us cluster.
+		guardrail := &batchv1.OSIndexPolicyGuardrail{
+			ObjectMeta: metav1.ObjectMeta{Name: "team-a"},
+			Spec: batchv1.OSIndexPolicyGuardrailSpec{
+				Namespaces:      []string{"team-a"},
+				AllowedClusters: []string{server.URL},
+			},
+		}
+		policy := &batchv1.OSIndexPolicy{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "logs"},
+			Spec: batchv1.OSIndexPolicySpec{
+				PolicyID: "logs",
+				Policy:   batchv1.OpensearchIndexPolicy{Description: "logs", DefaultState: "hot"},
+				Targets:  &batchv1.PolicyTargets{Clusters: []string{"eu", "us", "ap"}},
+			},
+		}
+		scheme := runtime.NewScheme()
+		Expect(batchv1.AddToScheme(scheme)).To(Succeed())
+		reconciler = &OSIndexPolicyReconciler{
+			Client:          fake.NewClientBuilder().WithScheme(scheme).WithObjects(policy, eu, us, guardrail).WithStatusSubresource(policy).Build(),
+			Scheme:          scheme,
+			Clients:         opensearch.NewClientCache(),
+			Recorder:        record.NewFakeRecorder(10),
+			ExplainInterval: time.Hour,
+		}
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("syncs every target on its own and reports each of them", func() {
+		_, err := reconciler.Reconcile(context.Background(), reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+
+		stored := &batchv1.OSIndexPolicy{}
+		Expect(reconciler.Get(context.Background(), key, stored)).To(Succeed())
+		Expect(stored.Status.Targets).To(HaveLen(3))
+		reasons := map[string]string{}
+		for _, target := range stored.Status.Targets {
+			reasons[target.Cluster] = meta.FindStatusCondition(target.Conditions, batchv1.ConditionSynced).Reason
+		}
+		Expect(reasons).To(HaveKeyWithValue("ap", "ClusterNotFound"))
+		Expect(reasons).To(HaveKeyWithValue("us", eventGuardrailViolation))
+		Expect(meta.IsStatusConditionTrue(stored.Status.Targets[1].Conditions, batchv1.ConditionSynced)).To(BeTrue())
+		Expect(stored.Status.Targets[1].URL).To(Equal(server.URL))
+		Expect(stored.Status.Targets[1].ClusterVersion).To(Equal("2.11.0"))
+
+		synced := meta.FindStatusCondition(stored.Status.Conditions, batchv1.ConditionSynced)
+		Expect(synced.Status).To(Equal(metav1.ConditionFalse))
+		Expect(synced.Reason).To(Equal("TargetsNotSynced"))
+		Expect(synced.Message).To(Equal("2 of 3 targets not synced: ap, us"))
+		Expect(stored.Status.ClusterVersion).To(BeEmpty())
+	})
+
+	It("requeues the policies targeting a cluster", func() {
+		Expect(reconciler.policiesForCluster(context.Background(), eu)).To(ConsistOf(
+			reconcile.Request{NamespacedName: key},
+		))
+
+		stored := &batchv1.OSIndexPolicy{}
+		Expect(reconciler.Get(context.Background(), key, stored)).To(Succeed())
+		stored.Spec.Targets = &batchv1.PolicyTargets{Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"tier": "metrics"}}}
+		Expect(reconciler.Update(context.Background(), stored)).To(Succeed())
+		Expect(reconciler.policiesForCluster(context.Background(), eu)).To(BeEmpty())
+	})
+
+	It("caches one client per target", func() {
+		policy := &batchv1.OSIndexPolicy{ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "logs"}}
+		Expect(clientOwner(context.Background(), policy)).To(Equal("team-a/logs"))
+		Expect(clientOwner(withTarget(context.Background(), "eu"), policy)).To(Equal("team-a/logs@eu"))
+	})
+})
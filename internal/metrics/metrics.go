@@ -0,0 +1,99 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package metrics defines the Prometheus metrics of the operator. They are
+// registered on the controller-runtime registry and served by the manager's
+// metrics endpoint next to the controller-runtime metrics.
+package metrics
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	// SyncStatus is 1 when the ISM policy in OpenSearch matches the spec, 0 otherwise.
+	SyncStatus = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "osindexpolicy_sync_status",
+		Help: "Whether the ISM policy in OpenSearch matches the OSIndexPolicy spec (1) or not (0).",
+	}, []string{"policy", "cluster"})
+
+	// DriftDetected counts policies found changed in OpenSearch behind the operator's back.
+	DriftDetected = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "osindexpolicy_drift_detected_total",
+		Help: "Number of times the ISM policy in OpenSearch was found to differ from an unchanged spec.",
+	}, []string{"policy", "cluster"})
+
+	// Updates counts ISM policy updates sent to OpenSearch.
+	Updates = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "osindexpolicy_updates_total",
+		Help: "Number of ISM policy updates sent to OpenSearch.",
+	}, []string{"policy", "cluster"})
+
+	// ManagedIndexFailures is the number of managed indices whose ISM action failed.
+	ManagedIndexFailures = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "osindexpolicy_managed_index_failures",
+		Help: "Number of indices managed by the ISM policy whose current action failed.",
+	}, []string{"policy", "cluster"})
+
+	// RequestDuration observes the OpenSearch API calls, one observation per attempt.
+	RequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "opensearch_request_duration_seconds",
+		Help:    "Latency of OpenSearch API requests by node, method and status code.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"endpoint", "method", "status"})
+)
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(
+		SyncStatus,
+		DriftDetected,
+		Updates,
+		ManagedIndexFailures,
+		RequestDuration,
+	)
+}
+
+// ObserveRequest records an OpenSearch request. A zero status code denotes a
+// request that failed without a response.
+func ObserveRequest(endpoint, method string, statusCode int, duration time.Duration) {
+	status := "error"
+	if statusCode != 0 {
+		status = strconv.Itoa(statusCode)
+	}
+	RequestDuration.WithLabelValues(endpoint, method, status).Observe(duration.Seconds())
+}
+
+// SetSynced records whether the policy is in sync with the cluster.
+func SetSynced(policy, cluster string, synced bool) {
+	value := 0.0
+	if synced {
+		value = 1
+	}
+	SyncStatus.WithLabelValues(policy, cluster).Set(value)
+}
+
+// Forget drops the series of a deleted policy.
+func Forget(policy string) {
+	labels := prometheus.Labels{"policy": policy}
+	SyncStatus.DeletePartialMatch(labels)
+	DriftDetected.DeletePartialMatch(labels)
+	Updates.DeletePartialMatch(labels)
+	ManagedIndexFailures.DeletePartialMatch(labels)
+}
@@ -0,0 +1,104 @@
+package guardrails
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/a8uhnf/opensearch-ism-crd/api/v1"
+)
+
+// Check returns the reasons the spec of an OSIndexPolicy in namespace breaks
+// the OSIndexPolicyGuardrails that apply to the namespace.
+func Check(ctx context.Context, reader client.Reader, namespace string, spec *apiv1.OSIndexPolicySpec) ([]string, error) {
+	guardrails := &apiv1.OSIndexPolicyGuardrailList{}
+	if err := reader.List(ctx, guardrails); err != nil {
+		return nil, fmt.Errorf("failed to list guardrails: %w", err)
+	}
+	return Violations(guardrails.Items, namespace, spec), nil
+}
+
+// Violations returns the reasons the spec breaks the guardrails matching the
+// namespace. Namespaces no guardrail matches are not restricted, and a
+// namespace matched by several guardrails may use what any of them allows.
+func Violations(guardrails []apiv1.OSIndexPolicyGuardrail, namespace string, spec *apiv1.OSIndexPolicySpec) []string {
+	var clusters, prefixes []string
+	matched, anyCluster, anyPrefix := false, false, false
+	for _, guardrail := range guardrails {
+		if !AppliesTo(&guardrail, namespace) {
+			continue
+		}
+		matched = true
+		anyCluster = anyCluster || len(guardrail.Spec.AllowedClusters) == 0
+		anyPrefix = anyPrefix || len(guardrail.Spec.AllowedIndexPatternPrefixes) == 0
+		clusters = append(clusters, guardrail.Spec.AllowedClusters...)
+		prefixes = append(prefixes, guardrail.Spec.AllowedIndexPatternPrefixes...)
+	}
+	if !matched {
+		return nil
+	}
+
+	var violations []string
+	if !anyCluster {
+		conn := spec.OpensearhConnection
+		for _, url := range append([]string{conn.URL}, conn.URLs...) {
+			if url != "" && !matchAny(clusters, url) {
+				violations = append(violations, fmt.Sprintf("cluster %s is not allowed in namespace %s", url, namespace))
+			}
+		}
+	}
+	if !anyPrefix {
+		for _, pattern := range indexPatterns(spec) {
+			if !hasAnyPrefix(prefixes, pattern) {
+				violations = append(violations, fmt.Sprintf("index pattern %q is not allowed in namespace %s, it must start with one of %s",
+					pattern, namespace, strings.Join(prefixes, ", ")))
+			}
+		}
+	}
+	return violations
+}
+
+// AppliesTo reports whether the guardrail restricts the namespace.
+func AppliesTo(guardrail *apiv1.OSIndexPolicyGuardrail, namespace string) bool {
+	return matchAny(guardrail.Spec.Namespaces, namespace)
+}
+
+// indexPatterns returns the index patterns the spec selects indices with.
+func indexPatterns(spec *apiv1.OSIndexPolicySpec) []string {
+	var patterns []string
+	if template := spec.Policy.ISMTemplate; template != nil {
+		patterns = append(patterns, template.IndexPatterns...)
+	}
+	if attach := spec.AttachExisting; attach != nil {
+		patterns = append(patterns, attach.IndexPatterns...)
+	}
+	return patterns
+}
+
+// matchAny reports whether value equals or matches one of the glob patterns.
+// Trailing slashes of URLs are ignored.
+func matchAny(patterns []string, value string) bool {
+	value = strings.TrimSuffix(value, "/")
+	for _, pattern := range patterns {
+		pattern = strings.TrimSuffix(pattern, "/")
+		if pattern == value {
+			return true
+		}
+		if ok, err := path.Match(pattern, value); err == nil && ok {
+			return true
+		}
+	}
+	return false
+}
+
+func hasAnyPrefix(prefixes []string, pattern string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(pattern, prefix) {
+			return true
+		}
+	}
+	return false
+}
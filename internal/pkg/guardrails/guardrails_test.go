@@ -0,0 +1,74 @@
+package guardrails
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/a8uhnf/opensearch-ism-crd/api/v1"
+)
+
+var _ = Describe("Guardrails", func() {
+	var (
+		guardrails []apiv1.OSIndexPolicyGuardrail
+		spec       *apiv1.OSIndexPolicySpec
+	)
+
+	BeforeEach(func() {
+		guardrails = []apiv1.OSIndexPolicyGuardrail{{
+			ObjectMeta: metav1.ObjectMeta{Name: "teams"},
+			Spec: apiv1.OSIndexPolicyGuardrailSpec{
+				Namespaces:                  []string{"team-*"},
+				AllowedClusters:             []string{"https://*.logs.example.com:9200"},
+				AllowedIndexPatternPrefixes: []string{"team-a-"},
+			},
+		}}
+		spec = &apiv1.OSIndexPolicySpec{
+			OpensearhConnection: apiv1.OpensearhConnection{URL: "https://eu.logs.example.com:9200/"},
+			Policy: apiv1.OpensearchIndexPolicy{
+				ISMTemplate: &apiv1.ISMTemplate{IndexPatterns: []string{"team-a-logs-*"}},
+			},
+		}
+	})
+
+	It("allows what the matching guardrails allow", func() {
+		Expect(Violations(guardrails, "team-a", spec)).To(BeEmpty())
+	})
+
+	It("does not restrict namespaces no guardrail matches", func() {
+		spec.Policy.ISMTemplate.IndexPatterns = []string{"*"}
+		Expect(Violations(guardrails, "platform", spec)).To(BeEmpty())
+	})
+
+	It("rejects other clusters and index patterns", func() {
+		spec.OpensearhConnection.URLs = []string{"https://opensearch.internal:9200"}
+		spec.AttachExisting = &apiv1.AttachExisting{IndexPatterns: []string{"*"}}
+		Expect(Violations(guardrails, "team-a", spec)).To(ConsistOf(
+			"cluster https://opensearch.internal:9200 is not allowed in namespace team-a",
+			`index pattern "*" is not allowed in namespace team-a, it must start with one of team-a-`,
+		))
+	})
+
+	It("combines the allowances of several guardrails", func() {
+		guardrails = append(guardrails, apiv1.OSIndexPolicyGuardrail{
+			Spec: apiv1.OSIndexPolicyGuardrailSpec{Namespaces: []string{"team-a"}},
+		})
+		spec.Policy.ISMTemplate.IndexPatterns = []string{"*"}
+		Expect(Violations(guardrails, "team-a", spec)).To(BeEmpty())
+	})
+
+	It("lists the guardrails from the cluster", func() {
+		scheme := runtime.NewScheme()
+		Expect(apiv1.AddToScheme(scheme)).To(Succeed())
+		reader := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&guardrails[0]).Build()
+		spec.Policy.ISMTemplate.IndexPatterns = []string{"team-b-*"}
+
+		violations, err := Check(context.Background(), reader, "team-b", spec)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(violations).To(HaveLen(1))
+	})
+})
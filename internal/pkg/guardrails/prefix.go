@@ -0,0 +1,105 @@
+package guardrails
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/a8uhnf/opensearch-ism-crd/api/v1"
+)
+
+// NamespacePrefix returns the prefix of the ISM policy IDs and index patterns
+// of the OSIndexPolicies in namespace, when the manager prefixes them.
+func NamespacePrefix(namespace string) string {
+	return namespace + "-"
+}
+
+// WithNamespacePrefix prefixes the policy ID and the ism_template and
+// attach_existing index patterns of the spec with the namespace, so that
+// policies of different namespaces neither collide in OpenSearch nor select
+// each other's indices. The prefix is always added, even to values that
+// already start with it, so that two different values never end up the same.
+func WithNamespacePrefix(spec *apiv1.OSIndexPolicySpec, namespace string) {
+	if namespace == "" {
+		return
+	}
+	prefix := NamespacePrefix(namespace)
+	spec.PolicyID = withPrefix(prefix, spec.PolicyID)
+	if template := spec.Policy.ISMTemplate; template != nil {
+		template.IndexPatterns = withPrefixes(prefix, template.IndexPatterns)
+	}
+	if attach := spec.AttachExisting; attach != nil {
+		attach.IndexPatterns = withPrefixes(prefix, attach.IndexPatterns)
+		attach.Exclude = withPrefixes(prefix, attach.Exclude)
+	}
+}
+
+func withPrefixes(prefix string, values []string) []string {
+	if values == nil {
+		return nil
+	}
+	prefixed := make([]string, len(values))
+	for i, value := range values {
+		prefixed[i] = withPrefix(prefix, value)
+	}
+	return prefixed
+}
+
+func withPrefix(prefix, value string) string {
+	return prefix + value
+}
+
+// CheckPrefix returns the reasons the prefixed spec of a policy in namespace
+// reaches into the prefix of another namespace.
+func CheckPrefix(ctx context.Context, reader client.Reader, namespace string, spec *apiv1.OSIndexPolicySpec) ([]string, error) {
+	namespaces := &corev1.NamespaceList{}
+	if err := reader.List(ctx, namespaces); err != nil {
+		return nil, fmt.Errorf("failed to list namespaces: %w", err)
+	}
+	names := make([]string, 0, len(namespaces.Items))
+	for _, ns := range namespaces.Items {
+		names = append(names, ns.Name)
+	}
+	return PrefixViolations(names, namespace, spec), nil
+}
+
+// PrefixViolations returns the reasons the spec, prefixed with namespace,
+// reaches into the prefix of one of the other namespaces: namespace names may
+// contain dashes, so policy a-logs of namespace team and policy logs of
+// namespace team-a would otherwise both become team-a-logs. Index patterns may
+// not select indices in the prefix of another namespace either.
+func PrefixViolations(namespaces []string, namespace string, spec *apiv1.OSIndexPolicySpec) []string {
+	own := NamespacePrefix(namespace)
+	var violations []string
+	for _, other := range namespaces {
+		prefix := NamespacePrefix(other)
+		// Only the namespaces whose prefix extends the own prefix can be reached.
+		if other == namespace || !strings.HasPrefix(prefix, own) {
+			continue
+		}
+		if strings.HasPrefix(spec.PolicyID, prefix) {
+			violations = append(violations, fmt.Sprintf("policy ID %q falls into the prefix of namespace %s", spec.PolicyID, other))
+		}
+		for _, pattern := range indexPatterns(spec) {
+			if patternReaches(pattern, prefix) {
+				violations = append(violations, fmt.Sprintf("index pattern %q selects indices in the prefix of namespace %s", pattern, other))
+			}
+		}
+	}
+	return violations
+}
+
+// patternReaches reports whether the pattern may match names starting with prefix.
+func patternReaches(pattern, prefix string) bool {
+	literal := pattern
+	if i := strings.IndexAny(pattern, "*?"); i >= 0 {
+		literal = pattern[:i]
+		if strings.HasPrefix(prefix, literal) {
+			return true
+		}
+	}
+	return strings.HasPrefix(literal, prefix)
+}
@@ -0,0 +1,70 @@
+package guardrails
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	apiv1 "github.com/a8uhnf/opensearch-ism-crd/api/v1"
+)
+
+var _ = Describe("Namespace prefix", func() {
+	var spec *apiv1.OSIndexPolicySpec
+
+	BeforeEach(func() {
+		spec = &apiv1.OSIndexPolicySpec{
+			PolicyID: "logs-retention",
+			Policy: apiv1.OpensearchIndexPolicy{
+				ISMTemplate: &apiv1.ISMTemplate{IndexPatterns: []string{"logs-*", "team-a-audit-*"}},
+			},
+			AttachExisting: &apiv1.AttachExisting{IndexPatterns: []string{"*"}, Exclude: []string{"*-restored"}},
+		}
+	})
+
+	It("prefixes the policy ID and index patterns with the namespace", func() {
+		WithNamespacePrefix(spec, "team-a")
+		Expect(spec.PolicyID).To(Equal("team-a-logs-retention"))
+		Expect(spec.Policy.ISMTemplate.IndexPatterns).To(Equal([]string{"team-a-logs-*", "team-a-team-a-audit-*"}))
+		Expect(spec.AttachExisting.IndexPatterns).To(Equal([]string{"team-a-*"}))
+		Expect(spec.AttachExisting.Exclude).To(Equal([]string{"team-a-*-restored"}))
+	})
+
+	It("prefixes values already starting with the prefix, so that they stay distinct", func() {
+		other := spec.DeepCopy()
+		other.PolicyID = "team-a-logs-retention"
+		WithNamespacePrefix(spec, "team-a")
+		WithNamespacePrefix(other, "team-a")
+		Expect(other.PolicyID).To(Equal("team-a-team-a-logs-retention"))
+		Expect(other.PolicyID).NotTo(Equal(spec.PolicyID))
+	})
+
+	It("leaves cluster-scoped specs unchanged", func() {
+		WithNamespacePrefix(spec, "")
+		Expect(spec.PolicyID).To(Equal("logs-retention"))
+	})
+	DescribeTable("rejects values reaching into the prefix of another namespace",
+		func(policyID string, pattern string, want []string) {
+			spec := &apiv1.OSIndexPolicySpec{
+				PolicyID: policyID,
+				Policy: apiv1.OpensearchIndexPolicy{
+					ISMTemplate: &apiv1.ISMTemplate{IndexPatterns: []string{pattern}},
+				},
+			}
+			WithNamespacePrefix(spec, "team")
+			Expect(PrefixViolations([]string{"team", "team-a", "platform", "te"}, "team", spec)).To(Equal(want))
+		},
+		Entry("own values", "logs", "logs-*", nil),
+		Entry("policy ID of another namespace", "a-logs", "logs-*", []string{
+			`policy ID "team-a-logs" falls into the prefix of namespace team-a`,
+		}),
+		Entry("pattern in another namespace", "logs", "a-logs-*", []string{
+			`index pattern "team-a-logs-*" selects indices in the prefix of namespace team-a`,
+		}),
+		Entry("pattern selecting another namespace", "logs", "*", []string{
+			`index pattern "team-*" selects indices in the prefix of namespace team-a`,
+		}),
+		Entry("pattern close to another namespace", "logs", "a*", []string{
+			`index pattern "team-a*" selects indices in the prefix of namespace team-a`,
+		}),
+		Entry("pattern next to another namespace", "logs", "ab-*", nil),
+	)
+})
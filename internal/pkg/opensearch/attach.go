@@ -0,0 +1,88 @@
+package opensearch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// unmanagedIndex is the explain API representation of an index, managed or not.
+// Unmanaged indices only report null policy settings.
+type unmanagedIndex struct {
+	PolicyID           string  `json:"policy_id"`
+	PluginsPolicyID    *string `json:"index.plugins.index_state_management.policy_id"`
+	OpendistroPolicyID *string `json:"index.opendistro.index_state_management.policy_id"`
+}
+
+func (u unmanagedIndex) managed() bool {
+	return u.PolicyID != "" || (u.PluginsPolicyID != nil && *u.PluginsPolicyID != "") ||
+		(u.OpendistroPolicyID != nil && *u.OpendistroPolicyID != "")
+}
+
+// UnmanagedIndices returns the indices matching the patterns that no ISM policy
+// manages, sorted by name.
+func (c *openSearchClient) UnmanagedIndices(ctx context.Context, patterns []string) ([]string, error) {
+	logr := logf.FromContext(ctx)
+	info, err := c.ClusterInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", fmt.Sprintf("/%s/explain/%s", info.ISMPrefix(), strings.Join(patterns, ",")), nil)
+	if err != nil {
+		return nil, errors.NewInternalError(err)
+	}
+	resp, err := c.perform(ctx, req)
+	if err != nil {
+		logr.Error(err, "Failed to explain indices")
+		if _, ok := IsCircuitOpen(err); ok {
+			return nil, err
+		}
+		return nil, errors.NewInternalError(err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode == http.StatusNotFound {
+		// A pattern without wildcard names a missing index.
+		return nil, nil
+	}
+	if resp.StatusCode >= 300 {
+		return nil, errors.NewInternalError(fmt.Errorf("failed to explain indices: %d", resp.StatusCode))
+	}
+	explained := map[string]json.RawMessage{}
+	if err := json.NewDecoder(resp.Body).Decode(&explained); err != nil {
+		return nil, errors.NewInternalError(err)
+	}
+
+	var indices []string
+	for name, raw := range explained {
+		index := unmanagedIndex{}
+		if err := json.Unmarshal(raw, &index); err != nil {
+			// Not an index, e.g. total_managed_indices.
+			continue
+		}
+		if !index.managed() {
+			indices = append(indices, name)
+		}
+	}
+	sort.Strings(indices)
+	return indices, nil
+}
+
+// AddPolicy attaches the policy to the indices with the ISM add API, in batches.
+func (c *openSearchClient) AddPolicy(ctx context.Context, indices []string, policyID string) (*ChangePolicyResult, error) {
+	result, err := c.postIndices(ctx, "add", indices, map[string]string{"policy_id": policyID})
+	if err != nil {
+		return nil, err
+	}
+	logf.FromContext(ctx).Info("Attached policy to existing indices", "policyID", policyID,
+		"updated", result.UpdatedIndices, "failed", len(result.FailedIndices))
+	return result, nil
+}
@@ -0,0 +1,60 @@
+package opensearch
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Authentication", func() {
+	var (
+		server *httptest.Server
+		mu     sync.Mutex
+		auth   []string
+	)
+
+	BeforeEach(func() {
+		auth = nil
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			auth = append(auth, r.Header.Get("Authorization"))
+			mu.Unlock()
+			_, _ = w.Write([]byte(`{"version":{"distribution":"opensearch","number":"2.11.0"}}`))
+		}))
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	DescribeTable("sends the configured Authorization header",
+		func(config OpenSearchConfig, want string) {
+			config.URL = server.URL
+			client, err := NewOpenSearchClient(context.Background(), config)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = client.ClusterInfo(context.Background())
+			Expect(err).NotTo(HaveOccurred())
+			mu.Lock()
+			defer mu.Unlock()
+			Expect(auth).To(ConsistOf(want))
+		},
+		Entry("basic", OpenSearchConfig{Username: "admin", Password: "admin"}, "Basic YWRtaW46YWRtaW4="),
+		Entry("bearer token", OpenSearchConfig{BearerToken: "jwt"}, "Bearer jwt"),
+		Entry("api key", OpenSearchConfig{APIKey: "key"}, "ApiKey key"),
+		Entry("anonymous", OpenSearchConfig{}, ""),
+	)
+
+	It("rejects more than one authentication mode", func() {
+		_, err := NewOpenSearchClient(context.Background(), OpenSearchConfig{
+			URL:         server.URL,
+			Username:    "admin",
+			BearerToken: "jwt",
+		})
+		Expect(err).To(MatchError(ContainSubstring("basic, bearer token")))
+	})
+})
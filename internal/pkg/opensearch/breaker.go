@@ -0,0 +1,106 @@
+package opensearch
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// BreakerConfig controls when a cluster's circuit breaker opens.
+type BreakerConfig struct {
+	// FailureThreshold is the number of consecutive failed calls that opens the circuit.
+	// Zero disables the breaker.
+	FailureThreshold int
+	// OpenDuration is how long the circuit stays open before a probe call is let through.
+	OpenDuration time.Duration
+}
+
+// DefaultBreakerConfig returns the breaker settings used when none are configured.
+func DefaultBreakerConfig() BreakerConfig {
+	return BreakerConfig{
+		FailureThreshold: 5,
+		OpenDuration:     time.Minute,
+	}
+}
+
+// CircuitOpenError is returned, without calling OpenSearch, while a cluster's circuit is open.
+type CircuitOpenError struct {
+	// URL of the short-circuited cluster.
+	URL string
+	// RetryAfter is the time left until the circuit lets a probe call through.
+	RetryAfter time.Duration
+}
+
+func (e *CircuitOpenError) Error() string {
+	return fmt.Sprintf("circuit breaker open for %s, retry in %s", e.URL, e.RetryAfter.Round(time.Second))
+}
+
+// IsCircuitOpen returns the CircuitOpenError wrapped by err, if any.
+func IsCircuitOpen(err error) (*CircuitOpenError, bool) {
+	var coe *CircuitOpenError
+	if errors.As(err, &coe) {
+		return coe, true
+	}
+	return nil, false
+}
+
+// CircuitBreaker tracks consecutive failures against one cluster. Once the
+// threshold is reached it rejects calls for OpenDuration, then lets calls
+// through again: the first failure re-opens it, the first success closes it.
+type CircuitBreaker struct {
+	mu       sync.Mutex
+	url      string
+	config   BreakerConfig
+	failures int
+	openedAt time.Time
+	now      func() time.Time
+}
+
+// NewCircuitBreaker returns a closed breaker for the cluster at url.
+func NewCircuitBreaker(url string, config BreakerConfig) *CircuitBreaker {
+	return &CircuitBreaker{url: url, config: config, now: time.Now}
+}
+
+// Allow returns a CircuitOpenError if calls to the cluster must be skipped.
+func (b *CircuitBreaker) Allow() error {
+	if b == nil {
+		return nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.openedAt.IsZero() {
+		return nil
+	}
+	if left := b.config.OpenDuration - b.now().Sub(b.openedAt); left > 0 {
+		return &CircuitOpenError{URL: b.url, RetryAfter: left}
+	}
+	return nil
+}
+
+// Success records a successful call and closes the circuit.
+func (b *CircuitBreaker) Success() {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures = 0
+	b.openedAt = time.Time{}
+}
+
+// Failure records a failed call, opening the circuit once the threshold is hit.
+func (b *CircuitBreaker) Failure() {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	if b.config.FailureThreshold > 0 && b.failures >= b.config.FailureThreshold {
+		b.openedAt = b.now()
+	}
+}
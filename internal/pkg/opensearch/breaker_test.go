@@ -0,0 +1,59 @@
+package opensearch
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CircuitBreaker", func() {
+	var (
+		breaker *CircuitBreaker
+		now     time.Time
+	)
+
+	BeforeEach(func() {
+		now = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+		breaker = NewCircuitBreaker("http://a:9200", BreakerConfig{FailureThreshold: 2, OpenDuration: time.Minute})
+		breaker.now = func() time.Time { return now }
+	})
+
+	It("should open after the failure threshold and reject calls", func() {
+		breaker.Failure()
+		Expect(breaker.Allow()).To(Succeed())
+		breaker.Failure()
+
+		err := breaker.Allow()
+		coe, ok := IsCircuitOpen(err)
+		Expect(ok).To(BeTrue())
+		Expect(coe.RetryAfter).To(Equal(time.Minute))
+	})
+
+	It("should let a probe through after the open duration and re-open on failure", func() {
+		breaker.Failure()
+		breaker.Failure()
+		now = now.Add(time.Minute)
+		Expect(breaker.Allow()).To(Succeed())
+
+		breaker.Failure()
+		Expect(breaker.Allow()).To(HaveOccurred())
+	})
+
+	It("should close on success", func() {
+		breaker.Failure()
+		breaker.Failure()
+		now = now.Add(time.Minute)
+		breaker.Success()
+		breaker.Failure()
+		Expect(breaker.Allow()).To(Succeed())
+	})
+
+	It("should never open when disabled", func() {
+		breaker = NewCircuitBreaker("http://a:9200", BreakerConfig{})
+		for range 10 {
+			breaker.Failure()
+		}
+		Expect(breaker.Allow()).To(Succeed())
+	})
+})
@@ -0,0 +1,184 @@
+package opensearch
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// ClientCache keeps one OpenSearch client per connection identity so that
+// reconciles reuse pooled HTTP connections instead of dialing on every pass.
+//
+// Every caller identifies itself as an owner (e.g. the namespaced name of the
+// reconciled object). When an owner switches to a different connection, or is
+// released, entries no longer used by anyone are dropped and their idle
+// connections closed.
+type ClientCache struct {
+	// Retry is applied to every client created by the cache.
+	Retry RetryConfig
+	// Breaker configures the circuit breaker shared by all clients of a cluster.
+	Breaker BreakerConfig
+
+	mu       sync.Mutex
+	entries  map[string]*cacheEntry
+	owners   map[string]string
+	breakers map[string]*CircuitBreaker
+
+	// newClient builds the client for a cache miss, overridable in tests.
+	newClient func(ctx context.Context, config OpenSearchConfig) (OpenSearch, error)
+}
+
+type cacheEntry struct {
+	client  OpenSearch
+	config  OpenSearchConfig
+	sources map[string]struct{}
+	owners  map[string]struct{}
+}
+
+// NewClientCache returns an empty ClientCache.
+func NewClientCache() *ClientCache {
+	return &ClientCache{
+		Retry:     DefaultRetryConfig(),
+		Breaker:   DefaultBreakerConfig(),
+		entries:   map[string]*cacheEntry{},
+		owners:    map[string]string{},
+		breakers:  map[string]*CircuitBreaker{},
+		newClient: NewOpenSearchClient,
+	}
+}
+
+// Get returns the client cached under key, creating it from config on a miss.
+// The key must change whenever anything that affects the connection changes
+// (endpoint, credentials, TLS material). Sources name the objects the
+// connection was built from so that EvictSource can drop it when they change.
+func (c *ClientCache) Get(ctx context.Context, owner, key string, sources []string, config OpenSearchConfig) (OpenSearch, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		config.Retry = c.Retry
+		config.Breaker = c.breakerLocked(config.URL)
+		cli, err := c.newClient(ctx, config)
+		if err != nil {
+			return nil, err
+		}
+		entry = &cacheEntry{
+			client:  cli,
+			config:  config,
+			sources: map[string]struct{}{},
+			owners:  map[string]struct{}{},
+		}
+		for _, s := range sources {
+			entry.sources[s] = struct{}{}
+		}
+		c.entries[key] = entry
+	}
+	// Released once the new entry holds the breaker, so that switching to
+	// another connection to the same cluster keeps its state.
+	if prev, ok := c.owners[owner]; ok && prev != key {
+		c.releaseLocked(ctx, owner, prev)
+	}
+	entry.owners[owner] = struct{}{}
+	c.owners[owner] = key
+	return entry.client, nil
+}
+
+// Release drops the owner's reference, closing the connection once unused.
+func (c *ClientCache) Release(ctx context.Context, owner string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if key, ok := c.owners[owner]; ok {
+		c.releaseLocked(ctx, owner, key)
+	}
+}
+
+// ReleasePrefix drops the references of every owner whose name starts with
+// prefix, e.g. the per-cluster owners of an object synced to several clusters.
+func (c *ClientCache) ReleasePrefix(ctx context.Context, prefix string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for owner, key := range c.owners {
+		if strings.HasPrefix(owner, prefix) {
+			c.releaseLocked(ctx, owner, key)
+		}
+	}
+}
+
+// EvictSource drops every client built from the given source, regardless of
+// its owners, and returns the number of evicted clients.
+func (c *ClientCache) EvictSource(ctx context.Context, source string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	evicted := 0
+	for key, entry := range c.entries {
+		if _, ok := entry.sources[source]; !ok {
+			continue
+		}
+		for owner := range entry.owners {
+			delete(c.owners, owner)
+		}
+		c.evictLocked(ctx, key)
+		evicted++
+	}
+	return evicted
+}
+
+// Len returns the number of cached clients.
+func (c *ClientCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
+
+// breakerLocked returns the circuit breaker of the cluster at url. Breakers
+// outlive the entries EvictSource drops so that reconnecting does not reset a
+// failing cluster, and are dropped with the last entry released for their url.
+func (c *ClientCache) breakerLocked(url string) *CircuitBreaker {
+	b, ok := c.breakers[url]
+	if !ok {
+		b = NewCircuitBreaker(url, c.Breaker)
+		c.breakers[url] = b
+	}
+	return b
+}
+
+func (c *ClientCache) releaseLocked(ctx context.Context, owner, key string) {
+	delete(c.owners, owner)
+	entry, ok := c.entries[key]
+	if !ok {
+		return
+	}
+	delete(entry.owners, owner)
+	if len(entry.owners) == 0 {
+		c.evictLocked(ctx, key)
+		c.forgetBreakerLocked(entry.config.URL)
+	}
+}
+
+// forgetBreakerLocked drops the circuit breaker of url once no cached client uses it.
+func (c *ClientCache) forgetBreakerLocked(url string) {
+	for _, entry := range c.entries {
+		if entry.config.URL == url {
+			return
+		}
+	}
+	delete(c.breakers, url)
+}
+
+func (c *ClientCache) evictLocked(ctx context.Context, key string) {
+	entry := c.entries[key]
+	delete(c.entries, key)
+	if closer, ok := entry.client.(interface{ Close() }); ok {
+		closer.Close()
+	}
+	if entry.config.TLSConfig != nil {
+		entry.config.TLSConfig.CloseIdleConnections()
+	}
+	logf.FromContext(ctx).V(1).Info("Evicted cached OpenSearch client", "url", entry.config.URL)
+}
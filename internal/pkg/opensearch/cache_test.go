@@ -0,0 +1,110 @@
+package opensearch
+
+import (
+	"context"
+	"net/http"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ClientCache", func() {
+	var (
+		ctx     context.Context
+		cache   *ClientCache
+		created int
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		created = 0
+		cache = NewClientCache()
+		cache.newClient = func(_ context.Context, config OpenSearchConfig) (OpenSearch, error) {
+			created++
+			return &openSearchClient{url: config.URL}, nil
+		}
+	})
+
+	It("should reuse the client for the same connection key", func() {
+		config := OpenSearchConfig{URL: "http://a:9200", TLSConfig: &http.Transport{}}
+		first, err := cache.Get(ctx, "default/a", "key-a", nil, config)
+		Expect(err).NotTo(HaveOccurred())
+		second, err := cache.Get(ctx, "default/b", "key-a", nil, config)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(second).To(BeIdenticalTo(first))
+		Expect(created).To(Equal(1))
+		Expect(cache.Len()).To(Equal(1))
+	})
+
+	It("should drop the old client when its only owner switches connection", func() {
+		_, err := cache.Get(ctx, "default/a", "key-a", nil, OpenSearchConfig{URL: "http://a:9200"})
+		Expect(err).NotTo(HaveOccurred())
+		_, err = cache.Get(ctx, "default/a", "key-b", nil, OpenSearchConfig{URL: "http://b:9200"})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(created).To(Equal(2))
+		Expect(cache.Len()).To(Equal(1))
+	})
+
+	It("should keep a shared client until the last owner is released", func() {
+		_, err := cache.Get(ctx, "default/a", "key-a", nil, OpenSearchConfig{URL: "http://a:9200"})
+		Expect(err).NotTo(HaveOccurred())
+		_, err = cache.Get(ctx, "default/b", "key-a", nil, OpenSearchConfig{URL: "http://a:9200"})
+		Expect(err).NotTo(HaveOccurred())
+
+		cache.Release(ctx, "default/a")
+		Expect(cache.Len()).To(Equal(1))
+		cache.Release(ctx, "default/b")
+		Expect(cache.Len()).To(BeZero())
+	})
+
+	It("should release every owner with a prefix", func() {
+		_, err := cache.Get(ctx, "default/a@eu", "key-a", nil, OpenSearchConfig{URL: "http://a:9200"})
+		Expect(err).NotTo(HaveOccurred())
+		_, err = cache.Get(ctx, "default/a@us", "key-b", nil, OpenSearchConfig{URL: "http://b:9200"})
+		Expect(err).NotTo(HaveOccurred())
+		_, err = cache.Get(ctx, "default/ab", "key-b", nil, OpenSearchConfig{URL: "http://b:9200"})
+		Expect(err).NotTo(HaveOccurred())
+
+		cache.ReleasePrefix(ctx, "default/a@")
+		Expect(cache.Len()).To(Equal(1))
+		cache.Release(ctx, "default/ab")
+		Expect(cache.Len()).To(BeZero())
+	})
+
+	It("should drop the breaker of a cluster once its last client is released", func() {
+		_, err := cache.Get(ctx, "default/a", "key-a", nil, OpenSearchConfig{URL: "http://a:9200"})
+		Expect(err).NotTo(HaveOccurred())
+		_, err = cache.Get(ctx, "default/b", "key-b", nil, OpenSearchConfig{URL: "http://a:9200"})
+		Expect(err).NotTo(HaveOccurred())
+		breaker := cache.breakers["http://a:9200"]
+
+		By("switching to another connection to the same cluster")
+		_, err = cache.Get(ctx, "default/a", "key-c", nil, OpenSearchConfig{URL: "http://a:9200"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cache.breakers).To(HaveKeyWithValue("http://a:9200", BeIdenticalTo(breaker)))
+
+		cache.Release(ctx, "default/b")
+		Expect(cache.breakers).To(HaveKey("http://a:9200"))
+		_, err = cache.Get(ctx, "default/a", "key-d", nil, OpenSearchConfig{URL: "http://b:9200"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cache.breakers).NotTo(HaveKey("http://a:9200"))
+		cache.Release(ctx, "default/a")
+		Expect(cache.breakers).To(BeEmpty())
+	})
+
+	It("should evict every client built from a changed source", func() {
+		_, err := cache.Get(ctx, "default/a", "key-a", []string{"secret/default/creds"}, OpenSearchConfig{URL: "http://a:9200"})
+		Expect(err).NotTo(HaveOccurred())
+		_, err = cache.Get(ctx, "default/b", "key-b", nil, OpenSearchConfig{URL: "http://b:9200"})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(cache.EvictSource(ctx, "secret/default/creds")).To(Equal(1))
+		Expect(cache.Len()).To(Equal(1))
+
+		_, err = cache.Get(ctx, "default/a", "key-a", []string{"secret/default/creds"}, OpenSearchConfig{URL: "http://a:9200"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(created).To(Equal(3))
+	})
+})
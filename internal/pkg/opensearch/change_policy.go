@@ -0,0 +1,103 @@
+package opensearch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// changePolicyBatchSize bounds the number of indices named in one change_policy or add call.
+const changePolicyBatchSize = 100
+
+// ChangePolicyRequest describes a change_policy call.
+type ChangePolicyRequest struct {
+	// PolicyID is the policy the indices switch to.
+	PolicyID string `json:"policy_id"`
+	// State is the state of the new policy the indices move to, the current one when empty.
+	State string `json:"state,omitempty"`
+	// Include restricts the change to the indices currently in these states.
+	Include []ChangePolicyInclude `json:"include,omitempty"`
+}
+
+// ChangePolicyInclude selects indices by their current state.
+type ChangePolicyInclude struct {
+	State string `json:"state"`
+}
+
+// ChangePolicyResult is the outcome of a change_policy or add call.
+type ChangePolicyResult struct {
+	// UpdatedIndices is the number of indices that will switch policy.
+	UpdatedIndices int `json:"updated_indices"`
+	// FailedIndices lists the indices that were rejected.
+	FailedIndices []ChangePolicyFailure `json:"failed_indices"`
+}
+
+// ChangePolicyFailure is an index rejected by change_policy or add.
+type ChangePolicyFailure struct {
+	Index  string `json:"index_name"`
+	Reason string `json:"reason"`
+}
+
+// ChangePolicy calls change_policy on the indices, in batches. ISM applies the
+// change to an index once its current action completes.
+func (c *openSearchClient) ChangePolicy(ctx context.Context, indices []string, change ChangePolicyRequest) (*ChangePolicyResult, error) {
+	result, err := c.postIndices(ctx, "change_policy", indices, change)
+	if err != nil {
+		return nil, err
+	}
+	logf.FromContext(ctx).Info("Changed policy of managed indices", "policyID", change.PolicyID, "state", change.State,
+		"updated", result.UpdatedIndices, "failed", len(result.FailedIndices))
+	return result, nil
+}
+
+// postIndices posts the body to an ISM API taking a list of indices in its
+// path, e.g. change_policy or add, in batches, and sums up the results. Index
+// names cannot contain commas or slashes, so they are listed in the path as is.
+func (c *openSearchClient) postIndices(ctx context.Context, api string, indices []string, request interface{}) (*ChangePolicyResult, error) {
+	logr := logf.FromContext(ctx)
+	info, err := c.ClusterInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, errors.NewInternalError(err)
+	}
+
+	result := &ChangePolicyResult{}
+	for start := 0; start < len(indices); start += changePolicyBatchSize {
+		batch := indices[start:min(start+changePolicyBatchSize, len(indices))]
+		path := fmt.Sprintf("/%s/%s/%s", info.ISMPrefix(), api, strings.Join(batch, ","))
+		req, err := http.NewRequest("POST", path, bytes.NewReader(body))
+		if err != nil {
+			return nil, errors.NewInternalError(err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := c.perform(ctx, req)
+		if err != nil {
+			logr.Error(err, "Failed to call ISM API", "api", api)
+			if _, ok := IsCircuitOpen(err); ok {
+				return nil, err
+			}
+			return nil, errors.NewInternalError(err)
+		}
+		batchResult := ChangePolicyResult{}
+		err = json.NewDecoder(resp.Body).Decode(&batchResult)
+		_ = resp.Body.Close()
+		if resp.StatusCode >= 300 {
+			return nil, errors.NewInternalError(fmt.Errorf("failed to call %s: %d", api, resp.StatusCode))
+		}
+		if err != nil {
+			return nil, errors.NewInternalError(err)
+		}
+		result.UpdatedIndices += batchResult.UpdatedIndices
+		result.FailedIndices = append(result.FailedIndices, batchResult.FailedIndices...)
+	}
+	return result, nil
+}
@@ -3,22 +3,175 @@ package opensearch
 import (
 	"bytes"
 	"context"
+	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	apiv1 "github.com/a8uhnf/opensearch-ism-crd/api/v1"
+	"github.com/a8uhnf/opensearch-ism-crd/internal/metrics"
 	"github.com/opensearch-project/opensearch-go"
+	"io"
 	"io/ioutil"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"net/http"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sync"
+	"time"
 )
 
-// OpensearchIndexPolicy represents an index policy in OpenSearch.
+// policyResource names ISM policies in the errors returned to callers.
+var policyResource = schema.GroupResource{
+	Group:    apiv1.GroupVersion.Group,
+	Resource: apiv1.GroupVersion.WithResource("opensearchindexpolicies").Resource,
+}
+
+// IndexPolicy is an ISM policy as stored in OpenSearch.
+type IndexPolicy struct {
+	// ID is the policy ID.
+	ID string `json:"_id"`
+	// SeqNo and PrimaryTerm identify the version of the policy for optimistic concurrency control.
+	SeqNo       int64 `json:"_seq_no"`
+	PrimaryTerm int64 `json:"_primary_term"`
+	// Policy is the policy document, including the fields OpenSearch adds such
+	// as last_updated_time and the default retry of every action.
+	Policy json.RawMessage `json:"policy"`
+}
 
 type openSearchClient struct {
-	client *opensearch.Client
-	url    string
+	client  *opensearch.Client
+	url     string
+	retry   RetryConfig
+	breaker *CircuitBreaker
+
+	// stop ends periodic node discovery, nil when it is disabled.
+	stop     chan struct{}
+	stopOnce sync.Once
+
+	// info is detected once, on the first call that needs it.
+	infoMu sync.Mutex
+	info   *ClusterInfo
+}
+
+// ClusterInfo queries the root endpoint once and returns the cluster distribution and version.
+func (c *openSearchClient) ClusterInfo(ctx context.Context) (ClusterInfo, error) {
+	c.infoMu.Lock()
+	defer c.infoMu.Unlock()
+	if c.info != nil {
+		return *c.info, nil
+	}
+
+	logr := logf.FromContext(ctx)
+	req, err := http.NewRequest("GET", "/", nil)
+	if err != nil {
+		return ClusterInfo{}, errors.NewInternalError(err)
+	}
+	resp, err := c.perform(ctx, req)
+	if err != nil {
+		logr.Error(err, "Failed to retrieve cluster info")
+		if _, ok := IsCircuitOpen(err); ok {
+			return ClusterInfo{}, err
+		}
+		return ClusterInfo{}, errors.NewInternalError(err)
+	}
+	defer resp.Body.Close()
+
+	info := ClusterInfo{Distribution: DistributionOpenSearch}
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		// Credentials restricted to ISM may not read the root endpoint,
+		// assume a current OpenSearch rather than failing every call.
+		logr.Info("Cannot detect OpenSearch version, assuming OpenSearch", "statusCode", resp.StatusCode)
+		c.info = &info
+		return info, nil
+	}
+	if resp.StatusCode >= 300 {
+		// Other failures are transient, detection runs again on the next call.
+		return ClusterInfo{}, errors.NewInternalError(fmt.Errorf("failed to retrieve cluster info: %d", resp.StatusCode))
+	}
+	var root struct {
+		Version struct {
+			Distribution string `json:"distribution"`
+			Number       string `json:"number"`
+		} `json:"version"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&root); err != nil {
+		logr.Error(err, "Failed to decode cluster info response")
+		return ClusterInfo{}, errors.NewInternalError(err)
+	}
+	// Only OpenSearch reports a distribution, Elasticsearch based Open Distro does not.
+	if root.Version.Distribution == "" {
+		info.Distribution = DistributionOpenDistro
+	}
+	info.Version = root.Version.Number
+	logr.Info("Detected OpenSearch cluster", "distribution", info.Distribution, "version", info.Version)
+	c.info = &info
+	return info, nil
+}
+
+// policyURL returns the ISM policy path, using the API prefix of the detected distribution.
+// Paths are relative so that the transport picks the node of every attempt.
+func (c *openSearchClient) policyURL(ctx context.Context, policyName string) (string, error) {
+	info, err := c.ClusterInfo(ctx)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("/%s/policies/%s", info.ISMPrefix(), policyName), nil
+}
+
+// perform sends the request through the circuit breaker, retrying idempotent
+// requests on transport errors and transient status codes. Every attempt goes
+// to the next live node, so requests that could not connect, which never
+// reached OpenSearch, are retried whatever their method.
+func (c *openSearchClient) perform(ctx context.Context, req *http.Request) (*http.Response, error) {
+	if err := c.breaker.Allow(); err != nil {
+		return nil, err
+	}
+	var body []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		var err error
+		if body, err = io.ReadAll(req.Body); err != nil {
+			return nil, err
+		}
+		_ = req.Body.Close()
+	}
+	idempotent := isIdempotent(req.Method)
+	for attempt := 1; ; attempt++ {
+		// The transport rewrites the URL and headers of the request it sends.
+		attemptReq := req.Clone(ctx)
+		if body != nil {
+			attemptReq.Body = io.NopCloser(bytes.NewReader(body))
+			attemptReq.ContentLength = int64(len(body))
+		}
+		start := time.Now()
+		resp, err := c.client.Transport.Perform(attemptReq)
+		statusCode := 0
+		if resp != nil {
+			statusCode = resp.StatusCode
+		}
+		// The transport has set the node the attempt was sent to, unless none was available.
+		endpoint := c.url
+		if attemptReq.URL.Host != "" {
+			endpoint = attemptReq.URL.Scheme + "://" + attemptReq.URL.Host
+		}
+		metrics.ObserveRequest(endpoint, req.Method, statusCode, time.Since(start))
+		transient := isConnectError(err) ||
+			(idempotent && (err != nil || isRetryableStatus(resp.StatusCode)))
+		if !transient || attempt > c.retry.MaxRetries || ctx.Err() != nil {
+			if err != nil || resp.StatusCode >= http.StatusInternalServerError {
+				c.breaker.Failure()
+			} else {
+				c.breaker.Success()
+			}
+			return resp, err
+		}
+		if resp != nil {
+			_, _ = io.Copy(io.Discard, resp.Body)
+			_ = resp.Body.Close()
+		}
+		logf.FromContext(ctx).V(1).Info("Retrying OpenSearch request", "method", req.Method, "path", req.URL.Path, "attempt", attempt, "error", err)
+		if err := sleep(ctx, c.retry.backoff(attempt-1)); err != nil {
+			return nil, err
+		}
+	}
 }
 
 func (c *openSearchClient) CreateIndexPolicy(ctx context.Context, policyName string, policy *apiv1.OpensearchIndexPolicy) error {
@@ -36,28 +189,32 @@ func (c *openSearchClient) CreateIndexPolicy(ctx context.Context, policyName str
 
 	body, err := json.Marshal(p)
 	bBody := bytes.NewBuffer(body)
-	fmt.Println("Creating index policy with body:", string(body))
-	fmt.Println("Creating index policy with body:", fmt.Sprintf("%s/_plugins/_ism/policies/%s", c.url, policyName))
+	policyURL, err := c.policyURL(ctx, policyName)
+	if err != nil {
+		return err
+	}
+	logr.V(1).Info("Creating index policy", "path", policyURL, "body", string(body))
 	// Create a new HTTP request to create the index policy
 	// Note: The OpenSearch client does not directly support creating index policies,
 	// so we need to use the HTTP API directly.
-	req, err := http.NewRequest("PUT", fmt.Sprintf("%s/_plugins/_ism/policies/%s", c.url, policyName), bBody)
+	req, err := http.NewRequest("PUT", policyURL, bBody)
 	req.Header.Set("Content-Type", "application/json")
 	if err != nil {
 		logr.Error(err, "Failed to create HTTP request for index policy")
 		return errors.NewInternalError(err)
 	}
-	resp, err := c.client.Transport.Perform(req)
+	resp, err := c.perform(ctx, req)
 	if err != nil {
 		logr.Error(err, "Failed to create index policy")
-
+		if _, ok := IsCircuitOpen(err); ok {
+			return err
+		}
 		return errors.NewInternalError(err)
 	}
-	defer req.Body.Close()
+	defer resp.Body.Close()
 	if resp.StatusCode >= 300 {
 		iout, _ := ioutil.ReadAll(resp.Body)
-		fmt.Println("Response body:", string(iout))
-		logr.Error(err, "Failed to create index policy")
+		logr.Error(err, "Failed to create index policy", "statusCode", resp.StatusCode, "response", string(iout))
 		return errors.NewInternalError(fmt.Errorf("failed to create policy: %d", resp.StatusCode))
 	}
 
@@ -66,61 +223,105 @@ func (c *openSearchClient) CreateIndexPolicy(ctx context.Context, policyName str
 	// to indicate that the operation was successful.
 	return nil
 }
-func (c *openSearchClient) GetIndexPolicy(ctx context.Context, policyName string) (*apiv1.OpensearchIndexPolicy, error) {
+func (c *openSearchClient) GetIndexPolicy(ctx context.Context, policyName string) (*IndexPolicy, error) {
 	// Implementation for retrieving an index policy from OpenSearch
 	logr := logf.FromContext(ctx)
 	logr.Info("Retrieving index policy", "policyName", policyName)
 	if policyName == "" {
 		return nil, errors.NewBadRequest("policyName cannot be empty")
 	}
+	policyURL, err := c.policyURL(ctx, policyName)
+	if err != nil {
+		return nil, err
+	}
 	// Create a new HTTP request to get the index policy
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s/_plugins/_ism/policies/%s", c.url, policyName), nil)
+	req, err := http.NewRequest("GET", policyURL, nil)
 	if err != nil {
 		logr.Error(err, "Failed to create HTTP request for index policy")
 		return nil, errors.NewInternalError(err)
 	}
 	logr.Info("Performing HTTP request to retrieve index policy", "policyName", policyName, "url", req.URL.String())
 
-	resp, err := c.client.Transport.Perform(req)
+	resp, err := c.perform(ctx, req)
+	if err != nil {
+		logr.Error(err, "Failed to retrieve index policy")
+		if _, ok := IsCircuitOpen(err); ok {
+			return nil, err
+		}
+		return nil, errors.NewInternalError(err)
+	}
 
+	defer resp.Body.Close()
 	if resp.StatusCode == 404 {
 		logr.Info("Failed to retrieve index policy")
 
-		schemaResource := schema.GroupResource{
-			Group:    apiv1.GroupVersion.Group,
-			Resource: apiv1.GroupVersion.WithResource("opensearchindexpolicies").Resource,
-		}
 		err = errors.NewNotFound(
-			schemaResource, fmt.Sprintf("index policy %s not found", policyName))
+			policyResource, fmt.Sprintf("index policy %s not found", policyName))
 
 		return nil, err
 	}
-	defer resp.Body.Close()
 	if resp.StatusCode >= 300 {
 		logr.Error(err, "not found")
 		return nil, errors.NewInternalError(fmt.Errorf("failed to retrieve policy: %d", resp.StatusCode))
 	}
-	// Here we would typically parse the response body to extract the index policy details.
-	// For simplicity, we return an empty OpensearchIndexPolicy.
 	logr.Info("Index policy retrieved successfully", "policyName", policyName)
-	// If the policy retrieval is successful, we can return an empty OpensearchIndexPolicy
-	// to indicate that the operation was successful.
 
-	policy := &apiv1.OpensearchIndexPolicy{}
-	jsonDecoder := json.NewDecoder(resp.Body)
-	logr.Info("Decoding index policy response")
-	// Decode the response body into the OpensearchIndexPolicy struct
-	// Assuming the response body is in JSON format and contains the policy details.
-	if err := jsonDecoder.Decode(policy); err != nil {
+	policy := &IndexPolicy{}
+	if err := json.NewDecoder(resp.Body).Decode(policy); err != nil {
 		logr.Error(err, "Failed to decode index policy response")
 		return nil, errors.NewInternalError(err)
 	}
-	// You would typically unmarshal the response body into an OpensearchIndexPolicy struct.
-	// For now, we return an empty policy as a placeholder.
-	// Assuming the response body contains the policy details, we would unmarshal it here.
-	// For example:
 	return policy, nil
 }
+
+// UpdateIndexPolicy replaces an index policy in OpenSearch and returns the stored
+// policy. The sequence number and primary term of the policy that was read guard
+// against concurrent updates.
+func (c *openSearchClient) UpdateIndexPolicy(ctx context.Context, policyName string, seqNo, primaryTerm int64, policy *apiv1.OpensearchIndexPolicy) (*IndexPolicy, error) {
+	logr := logf.FromContext(ctx)
+	logr.Info("Updating index policy", "policyName", policyName)
+	if policyName == "" {
+		return nil, errors.NewBadRequest("policyName cannot be empty")
+	}
+	body, err := json.Marshal(map[string]interface{}{"policy": policy})
+	if err != nil {
+		return nil, errors.NewInternalError(err)
+	}
+	policyURL, err := c.policyURL(ctx, policyName)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequest("PUT", fmt.Sprintf("%s?if_seq_no=%d&if_primary_term=%d", policyURL, seqNo, primaryTerm), bytes.NewReader(body))
+	if err != nil {
+		logr.Error(err, "Failed to create HTTP request for index policy")
+		return nil, errors.NewInternalError(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.perform(ctx, req)
+	if err != nil {
+		logr.Error(err, "Failed to update index policy")
+		if _, ok := IsCircuitOpen(err); ok {
+			return nil, err
+		}
+		return nil, errors.NewInternalError(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusConflict {
+		return nil, errors.NewConflict(policyResource, policyName, fmt.Errorf("index policy was modified concurrently"))
+	}
+	if resp.StatusCode >= 300 {
+		iout, _ := io.ReadAll(resp.Body)
+		logr.Error(nil, "Failed to update index policy", "statusCode", resp.StatusCode, "response", string(iout))
+		return nil, errors.NewInternalError(fmt.Errorf("failed to update policy: %d", resp.StatusCode))
+	}
+	updated := &IndexPolicy{}
+	if err := json.NewDecoder(resp.Body).Decode(updated); err != nil {
+		logr.Error(err, "Failed to decode index policy response")
+		return nil, errors.NewInternalError(err)
+	}
+	logr.Info("Index policy updated successfully", "policyName", policyName, "seqNo", updated.SeqNo)
+	return updated, nil
+}
 func (c *openSearchClient) DeleteIndexPolicy(ctx context.Context, policyName string) error {
 	// Implementation for deleting an index policy from OpenSearch
 	logr := logf.FromContext(ctx)
@@ -128,22 +329,29 @@ func (c *openSearchClient) DeleteIndexPolicy(ctx context.Context, policyName str
 	if policyName == "" {
 		return errors.NewBadRequest("policyName cannot be empty")
 	}
+	policyURL, err := c.policyURL(ctx, policyName)
+	if err != nil {
+		return err
+	}
 	// Create a new HTTP request to delete the index policy
-	req, err := http.NewRequest("DELETE", fmt.Sprintf("%s/_plugins/_ism/policies/%s", c.url, policyName), nil)
+	req, err := http.NewRequest("DELETE", policyURL, nil)
 	if err != nil {
 		logr.Error(err, "Failed to create HTTP request for deleting index policy")
 		return errors.NewInternalError(err)
 	}
-	resp, err := c.client.Transport.Perform(req)
+	resp, err := c.perform(ctx, req)
 	if err != nil {
 		logr.Error(err, "Failed to delete index policy")
+		if _, ok := IsCircuitOpen(err); ok {
+			return err
+		}
 		return errors.NewInternalError(err)
 	}
 	defer resp.Body.Close()
 	if resp.StatusCode >= 300 {
 		logr.Error(err, "Failed to delete index policy", "statusCode", resp.StatusCode)
 		// If the response status code indicates an error, we return an internal error.
-		return errors.NewInternalError(fmt.Errorf("failed to delete policy: %s", err.Error()))
+		return errors.NewInternalError(fmt.Errorf("failed to delete policy: %d", resp.StatusCode))
 	}
 	logr.Info("Index policy deleted successfully", "policyName", policyName)
 	return nil
@@ -159,14 +367,17 @@ func (c *openSearchClient) GetClusterHealth(ctx context.Context) (string, error)
 		logr.Error(err, "Failed to create HTTP request for cluster health")
 		return "", errors.NewInternalError(err)
 	}
-	resp, err := c.client.Transport.Perform(req)
+	resp, err := c.perform(ctx, req)
 	if err != nil {
 		logr.Error(err, "Failed to retrieve cluster health")
+		if _, ok := IsCircuitOpen(err); ok {
+			return "", err
+		}
 		return "", errors.NewInternalError(err)
 	}
 	defer resp.Body.Close()
 	if resp.StatusCode >= 300 {
-		return "", errors.NewInternalError(fmt.Errorf("failed to retrieve cluster health: %s", err.Error()))
+		return "", errors.NewInternalError(fmt.Errorf("failed to retrieve cluster health: %d", resp.StatusCode))
 	}
 	// Here we would typically parse the response body to extract the cluster health details.
 	// For simplicity, we return an empty string.
@@ -179,10 +390,52 @@ func (c *openSearchClient) GetClusterHealth(ctx context.Context) (string, error)
 type OpenSearchConfig struct {
 	// URL is the OpenSearch cluster URL.
 	URL string `json:"url"`
+	// Addresses lists further nodes of the cluster, used round-robin with URL.
+	Addresses []string `json:"addresses,omitempty"`
+	// DiscoverNodesOnStart replaces the nodes with those of the nodes info API when the client is created.
+	DiscoverNodesOnStart bool `json:"-"`
+	// DiscoverNodesInterval rediscovers the nodes periodically until the client is closed. Optional.
+	DiscoverNodesInterval time.Duration `json:"-"`
 	// Username is the username for OpenSearch authentication.
 	Username string `json:"username"`
 	// Password is the password for OpenSearch authentication.
 	Password string `json:"password"`
 	// TLSConfig contains TLS configuration for secure connections.
 	TLSConfig *http.Transport `json:"tls_config,omitempty"`
+	// BearerToken is sent as "Authorization: Bearer" instead of basic authentication. Optional.
+	BearerToken string `json:"-"`
+	// APIKey is sent as "Authorization: ApiKey" instead of basic authentication. Optional.
+	APIKey string `json:"-"`
+	// ClientCertificate is presented during the TLS handshake instead of using basic authentication. Optional.
+	ClientCertificate *tls.Certificate `json:"-"`
+	// AWS signs requests with AWS SigV4 instead of using basic authentication. Optional.
+	AWS *AWSConfig `json:"-"`
+	// Retry controls retries of idempotent requests.
+	Retry RetryConfig `json:"-"`
+	// Breaker short-circuits requests while the cluster is failing. Optional.
+	Breaker *CircuitBreaker `json:"-"`
+}
+
+// discoverNodes refreshes the nodes of the client every interval until Close is called.
+func (c *openSearchClient) discoverNodes(ctx context.Context, interval time.Duration) {
+	logr := logf.FromContext(ctx)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.stop:
+			return
+		case <-ticker.C:
+			if err := c.client.DiscoverNodes(); err != nil {
+				logr.Error(err, "Failed to discover OpenSearch nodes", "url", c.url)
+			}
+		}
+	}
+}
+
+// Close stops node discovery. The client must not be used afterwards.
+func (c *openSearchClient) Close() {
+	if c.stop != nil {
+		c.stopOnce.Do(func() { close(c.stop) })
+	}
 }
@@ -0,0 +1,141 @@
+package opensearch
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+
+	apiv1 "github.com/a8uhnf/opensearch-ism-crd/api/v1"
+)
+
+// DiffPolicy compares the desired policy with the policy document stored in
+// OpenSearch and returns the paths of the fields that differ, e.g.
+// "states[1].actions[0].delete". An empty result means the policy is in sync.
+//
+// Both directions are compared, so fields removed from the spec and fields
+// added in OpenSearch are reported. The fields OpenSearch maintains itself,
+// such as last_updated_time or the retry of every action, and zero values it
+// fills in, such as copy_alias false, are not drift.
+func DiffPolicy(desired *apiv1.OpensearchIndexPolicy, actual json.RawMessage) ([]string, error) {
+	raw, err := json.Marshal(desired)
+	if err != nil {
+		return nil, err
+	}
+	var want, got interface{}
+	if err := json.Unmarshal(raw, &want); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(actual, &got); err != nil {
+		return nil, fmt.Errorf("failed to decode index policy: %w", err)
+	}
+	withoutServerFields(got)
+	// The ISM template is a single object in the spec but a list in OpenSearch.
+	if w, ok := want.(map[string]interface{}); ok {
+		if template, ok := w["ism_template"]; ok {
+			w["ism_template"] = []interface{}{template}
+		}
+	}
+	var diffs []string
+	diffValues("", want, got, &diffs)
+	return diffs, nil
+}
+
+// withoutServerFields removes the fields OpenSearch maintains itself from a
+// stored policy: serverFields, the update time of the ISM templates and the
+// retry of every action, which the spec cannot set.
+func withoutServerFields(policy interface{}) {
+	p, ok := policy.(map[string]interface{})
+	if !ok {
+		return
+	}
+	for _, field := range serverFields {
+		delete(p, field)
+	}
+	templates, _ := p["ism_template"].([]interface{})
+	for _, template := range templates {
+		if t, ok := template.(map[string]interface{}); ok {
+			delete(t, "last_updated_time")
+		}
+	}
+	states, _ := p["states"].([]interface{})
+	for _, state := range states {
+		s, _ := state.(map[string]interface{})
+		actions, _ := s["actions"].([]interface{})
+		for _, action := range actions {
+			if a, ok := action.(map[string]interface{}); ok {
+				delete(a, "retry")
+			}
+		}
+	}
+}
+
+func diffValues(path string, want, got interface{}, diffs *[]string) {
+	switch w := want.(type) {
+	case map[string]interface{}:
+		g, ok := got.(map[string]interface{})
+		if !ok {
+			*diffs = append(*diffs, pathOrRoot(path))
+			return
+		}
+		keys := make([]string, 0, len(w)+len(g))
+		for k := range w {
+			keys = append(keys, k)
+		}
+		for k, gv := range g {
+			// Zero values are omitted from the spec.
+			if _, ok := w[k]; !ok && !isZeroJSON(gv) {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			child := k
+			if path != "" {
+				child = path + "." + k
+			}
+			wv, inWant := w[k]
+			gv, inGot := g[k]
+			if !inWant || !inGot {
+				*diffs = append(*diffs, child)
+				continue
+			}
+			diffValues(child, wv, gv, diffs)
+		}
+	case []interface{}:
+		g, ok := got.([]interface{})
+		if !ok || len(g) != len(w) {
+			*diffs = append(*diffs, pathOrRoot(path))
+			return
+		}
+		for i := range w {
+			diffValues(fmt.Sprintf("%s[%d]", path, i), w[i], g[i], diffs)
+		}
+	default:
+		if !scalarEqual(want, got) {
+			*diffs = append(*diffs, pathOrRoot(path))
+		}
+	}
+}
+
+// scalarEqual compares JSON scalars, treating numbers and their string form
+// alike since transition conditions are strings in the spec.
+func scalarEqual(want, got interface{}) bool {
+	if want == got {
+		return true
+	}
+	ws, wIsString := want.(string)
+	gn, gIsNumber := got.(float64)
+	if wIsString && gIsNumber {
+		n, err := strconv.ParseFloat(ws, 64)
+		return err == nil && n == gn
+	}
+	return false
+}
+
+func pathOrRoot(path string) string {
+	if path == "" {
+		return "policy"
+	}
+	return path
+}
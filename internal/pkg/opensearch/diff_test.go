@@ -0,0 +1,97 @@
+package opensearch
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	apiv1 "github.com/a8uhnf/opensearch-ism-crd/api/v1"
+)
+
+var _ = Describe("DiffPolicy", func() {
+	desired := &apiv1.OpensearchIndexPolicy{
+		Description:  "logs",
+		DefaultState: "hot",
+		States: []*apiv1.State{
+			{
+				Name: "hot",
+				Actions: []*apiv1.Action{
+					{RollOver: &apiv1.RollOverAction{MinSize: "50gb"}},
+				},
+				Transitions: []*apiv1.Transition{
+					{StateName: "delete", Conditions: map[string]string{"min_doc_count": "1000"}},
+				},
+			},
+			{
+				Name:    "delete",
+				Actions: []*apiv1.Action{{Delete: &apiv1.DeleteAction{}}},
+			},
+		},
+		ISMTemplate: &apiv1.ISMTemplate{IndexPatterns: []string{"logs-*"}, Priority: 100},
+	}
+
+	// stored is the policy as OpenSearch returns it, with the fields it adds.
+	stored := func(minSize, deleteAction string) []byte {
+		return []byte(`{
+			"policy_id": "logs",
+			"description": "logs",
+			"last_updated_time": 1700000000000,
+			"schema_version": 19,
+			"error_notification": null,
+			"default_state": "hot",
+			"states": [
+				{
+					"name": "hot",
+					"actions": [{"retry": {"count": 3, "backoff": "exponential", "delay": "1m"}, "rollover": {"min_size": "` + minSize + `", "copy_alias": false}}],
+					"transitions": [{"state_name": "delete", "conditions": {"min_doc_count": 1000}}]
+				},
+				{
+					"name": "delete",
+					"actions": [{"retry": {"count": 3, "backoff": "exponential", "delay": "1m"}, "` + deleteAction + `": {}}],
+					"transitions": []
+				}
+			],
+			"ism_template": [{"index_patterns": ["logs-*"], "priority": 100, "last_updated_time": 1700000000000}]
+		}`)
+	}
+
+	It("ignores the fields added by OpenSearch", func() {
+		Expect(DiffPolicy(desired, stored("50gb", "delete"))).To(BeEmpty())
+	})
+
+	It("reports the paths of changed fields", func() {
+		Expect(DiffPolicy(desired, stored("10gb", "read_only"))).To(Equal([]string{
+			"states[0].actions[0].rollover.min_size",
+			"states[1].actions[0].delete",
+		}))
+	})
+
+	It("reports lists of a different length", func() {
+		Expect(DiffPolicy(desired, []byte(`{"description": "logs", "default_state": "hot", "states": []}`))).To(ContainElements("states", "ism_template"))
+	})
+
+	It("reports fields removed from the spec", func() {
+		removed := desired.DeepCopy()
+		removed.ISMTemplate = nil
+		Expect(DiffPolicy(removed, stored("50gb", "delete"))).To(Equal([]string{"ism_template"}))
+	})
+
+	It("reports an error_notification removed from the spec", func() {
+		document := []byte(strings.Replace(string(stored("50gb", "delete")),
+			`"error_notification": null`, `"error_notification": {"channel": {"id": "ops"}, "message_template": {"source": "failed"}}`, 1))
+		Expect(DiffPolicy(desired, document)).To(Equal([]string{"error_notification"}))
+	})
+
+	It("reports a rollover condition removed from the spec", func() {
+		removed := desired.DeepCopy()
+		removed.States[0].Actions[0].RollOver = &apiv1.RollOverAction{MinIndexAge: "1d"}
+		document := []byte(strings.Replace(string(stored("50gb", "delete")), `"copy_alias": false`, `"copy_alias": false, "min_index_age": "1d"`, 1))
+		Expect(DiffPolicy(removed, document)).To(Equal([]string{"states[0].actions[0].rollover.min_size"}))
+	})
+
+	It("fails on a malformed document", func() {
+		_, err := DiffPolicy(desired, []byte(`[`))
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,209 @@
+package opensearch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// explainPageSize is the number of managed indices requested per explain call.
+const explainPageSize = 1000
+
+// ManagedIndex is the ISM state of an index, as reported by the explain API.
+type ManagedIndex struct {
+	// Index is the index name.
+	Index string
+	// PolicyID is the policy managing the index.
+	PolicyID string
+	// PolicySeqNo is the sequence number of the policy version the index runs.
+	PolicySeqNo int64
+	// State is the current state of the index, empty until ISM initialized it.
+	State string
+	// StateStartTime is when the index entered State.
+	StateStartTime time.Time
+	// Action is the current action of the index.
+	Action string
+	// Failed reports whether the current action failed.
+	Failed bool
+	// Message is the message ISM reported for the index, e.g. the failure cause.
+	Message string
+}
+
+// explainedIndex is the explain API representation of a managed index.
+type explainedIndex struct {
+	PolicyID    string `json:"policy_id"`
+	PolicySeqNo int64  `json:"policy_seq_no"`
+	State       *struct {
+		Name      string `json:"name"`
+		StartTime int64  `json:"start_time"`
+	} `json:"state"`
+	Action *struct {
+		Name   string `json:"name"`
+		Failed bool   `json:"failed"`
+	} `json:"action"`
+	RetryInfo *struct {
+		Failed bool `json:"failed"`
+	} `json:"retry_info"`
+	Info map[string]interface{} `json:"info"`
+}
+
+// ExplainPolicy returns the indices managed by the policy, sorted by index name.
+// With index patterns, only the matching indices are explained. Without, the
+// managed indices of the whole cluster are listed page by page.
+func (c *openSearchClient) ExplainPolicy(ctx context.Context, policyName string, indexPatterns []string) ([]ManagedIndex, error) {
+	info, err := c.ClusterInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(indexPatterns) > 0 {
+		page, err := c.explain(ctx, fmt.Sprintf("/%s/explain/%s", info.ISMPrefix(), strings.Join(indexPatterns, ",")))
+		if err != nil {
+			return nil, err
+		}
+		indices, _ := policyIndices(page, policyName)
+		sort.Slice(indices, func(i, j int) bool { return indices[i].Index < indices[j].Index })
+		return indices, nil
+	}
+
+	var indices []ManagedIndex
+	for from := 0; ; from += explainPageSize {
+		page, err := c.explain(ctx, fmt.Sprintf("/%s/explain?from=%d&size=%d", info.ISMPrefix(), from, explainPageSize))
+		if err != nil {
+			return nil, err
+		}
+		pageIndices, total := policyIndices(page, policyName)
+		indices = append(indices, pageIndices...)
+		if len(page) < explainPageSize || from+explainPageSize >= total {
+			break
+		}
+	}
+	sort.Slice(indices, func(i, j int) bool { return indices[i].Index < indices[j].Index })
+	return indices, nil
+}
+
+// explain sends an explain request and returns the response by index name,
+// without total_managed_indices when it is there. A missing index explains nothing.
+func (c *openSearchClient) explain(ctx context.Context, path string) (map[string]json.RawMessage, error) {
+	req, err := http.NewRequest("GET", path, nil)
+	if err != nil {
+		return nil, errors.NewInternalError(err)
+	}
+	resp, err := c.perform(ctx, req)
+	if err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to explain managed indices")
+		if _, ok := IsCircuitOpen(err); ok {
+			return nil, err
+		}
+		return nil, errors.NewInternalError(err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode == http.StatusNotFound {
+		// A pattern without wildcard names a missing index.
+		return map[string]json.RawMessage{}, nil
+	}
+	if resp.StatusCode >= 300 {
+		return nil, errors.NewInternalError(fmt.Errorf("failed to explain managed indices: %d", resp.StatusCode))
+	}
+	page := map[string]json.RawMessage{}
+	if err := json.NewDecoder(resp.Body).Decode(&page); err != nil {
+		return nil, errors.NewInternalError(err)
+	}
+	return page, nil
+}
+
+// policyIndices returns the indices of an explain response managed by the policy,
+// and the total_managed_indices it reports, which it removes from the page.
+func policyIndices(page map[string]json.RawMessage, policyName string) ([]ManagedIndex, int) {
+	var total int
+	if raw, ok := page["total_managed_indices"]; ok {
+		_ = json.Unmarshal(raw, &total)
+		delete(page, "total_managed_indices")
+	}
+	var indices []ManagedIndex
+	for name, raw := range page {
+		explained := explainedIndex{}
+		if err := json.Unmarshal(raw, &explained); err != nil {
+			// Not an index, e.g. a field added by a newer version.
+			continue
+		}
+		if explained.PolicyID != policyName {
+			continue
+		}
+		indices = append(indices, explained.managedIndex(name))
+	}
+	return indices, total
+}
+
+func (e explainedIndex) managedIndex(name string) ManagedIndex {
+	index := ManagedIndex{Index: name, PolicyID: e.PolicyID, PolicySeqNo: e.PolicySeqNo}
+	if e.State != nil {
+		index.State = e.State.Name
+		if e.State.StartTime > 0 {
+			index.StateStartTime = time.UnixMilli(e.State.StartTime).UTC()
+		}
+	}
+	if e.Action != nil {
+		index.Action = e.Action.Name
+		index.Failed = e.Action.Failed
+	}
+	if e.RetryInfo != nil && e.RetryInfo.Failed {
+		index.Failed = true
+	}
+	if message, ok := e.Info["message"].(string); ok {
+		index.Message = message
+	}
+	return index
+}
+
+// RetryManagedIndex retries the failed ISM action of an index.
+func (c *openSearchClient) RetryManagedIndex(ctx context.Context, index string) error {
+	logr := logf.FromContext(ctx)
+	info, err := c.ClusterInfo(ctx)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest("POST", fmt.Sprintf("/%s/retry/%s", info.ISMPrefix(), index), nil)
+	if err != nil {
+		return errors.NewInternalError(err)
+	}
+	resp, err := c.perform(ctx, req)
+	if err != nil {
+		logr.Error(err, "Failed to retry managed index", "index", index)
+		if _, ok := IsCircuitOpen(err); ok {
+			return err
+		}
+		return errors.NewInternalError(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return errors.NewInternalError(fmt.Errorf("failed to retry index %s: %d", index, resp.StatusCode))
+	}
+	var out struct {
+		Failures      bool `json:"failures"`
+		FailedIndices []struct {
+			Reason string `json:"reason"`
+		} `json:"failed_indices"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return errors.NewInternalError(err)
+	}
+	if out.Failures {
+		reason := "unknown reason"
+		if len(out.FailedIndices) > 0 {
+			reason = out.FailedIndices[0].Reason
+		}
+		return errors.NewInternalError(fmt.Errorf("failed to retry index %s: %s", index, reason))
+	}
+	logr.Info("Retried managed index", "index", index)
+	return nil
+}
@@ -0,0 +1,100 @@
+package opensearch
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	apiv1 "github.com/a8uhnf/opensearch-ism-crd/api/v1"
+)
+
+var _ = Describe("Failover", func() {
+	var (
+		ctx    context.Context
+		server *httptest.Server
+		mu     sync.Mutex
+		paths  []string
+		status int
+		retry  RetryConfig
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		paths = nil
+		status = http.StatusOK
+		retry = RetryConfig{MaxRetries: 2, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond}
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/" || r.URL.Path == "/cluster/" {
+				_, _ = w.Write([]byte(`{"version":{"distribution":"opensearch","number":"2.11.0"}}`))
+				return
+			}
+			mu.Lock()
+			paths = append(paths, r.Method+" "+r.URL.Path)
+			code := status
+			mu.Unlock()
+			w.WriteHeader(code)
+			_, _ = w.Write([]byte(`{}`))
+		}))
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	// deadURL returns the URL of a port nobody listens on.
+	deadURL := func() string {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).NotTo(HaveOccurred())
+		addr := listener.Addr().String()
+		Expect(listener.Close()).To(Succeed())
+		return "http://" + addr
+	}
+
+	It("fails over to the next node when a node refuses connections", func() {
+		client, err := NewOpenSearchClient(ctx, OpenSearchConfig{
+			URL:       deadURL(),
+			Addresses: []string{server.URL},
+			Retry:     retry,
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		// PUT is not idempotent but never reached the dead node.
+		Expect(client.CreateIndexPolicy(ctx, "logs", &apiv1.OpensearchIndexPolicy{Description: "logs"})).To(Succeed())
+		Expect(paths).To(Equal([]string{"PUT /_plugins/_ism/policies/logs"}))
+	})
+
+	It("keeps the path of the node URL on every attempt", func() {
+		status = http.StatusServiceUnavailable
+		client, err := NewOpenSearchClient(ctx, OpenSearchConfig{
+			URL:   server.URL + "/cluster",
+			Retry: retry,
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(client.DeleteIndexPolicy(ctx, "logs")).NotTo(Succeed())
+		Expect(paths).To(Equal([]string{
+			"DELETE /cluster/_plugins/_ism/policies/logs",
+			"DELETE /cluster/_plugins/_ism/policies/logs",
+			"DELETE /cluster/_plugins/_ism/policies/logs",
+		}))
+	})
+
+	It("stops node discovery when closed", func() {
+		client, err := NewOpenSearchClient(ctx, OpenSearchConfig{
+			URL:                   server.URL,
+			DiscoverNodesInterval: time.Hour,
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		closer, ok := client.(interface{ Close() })
+		Expect(ok).To(BeTrue())
+		closer.Close()
+		closer.Close()
+	})
+})
@@ -0,0 +1,206 @@
+package opensearch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	apiv1 "github.com/a8uhnf/opensearch-ism-crd/api/v1"
+)
+
+// listPageSize is the number of policies requested per list call.
+const listPageSize = 100
+
+// serverFields are the policy fields OpenSearch maintains itself.
+var serverFields = []string{"policy_id", "last_updated_time", "schema_version", "user"}
+
+// defaultRetry is the retry OpenSearch adds to every action without one.
+var defaultRetry = map[string]interface{}{"count": float64(3), "backoff": "exponential", "delay": "1m"}
+
+// ListIndexPolicies returns all the ISM policies of the cluster, sorted by ID.
+func (c *openSearchClient) ListIndexPolicies(ctx context.Context) ([]IndexPolicy, error) {
+	logr := logf.FromContext(ctx)
+	info, err := c.ClusterInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var policies []IndexPolicy
+	for from := 0; ; from += listPageSize {
+		req, err := http.NewRequest("GET", fmt.Sprintf("/%s/policies?from=%d&size=%d", info.ISMPrefix(), from, listPageSize), nil)
+		if err != nil {
+			return nil, errors.NewInternalError(err)
+		}
+		resp, err := c.perform(ctx, req)
+		if err != nil {
+			logr.Error(err, "Failed to list index policies")
+			if _, ok := IsCircuitOpen(err); ok {
+				return nil, err
+			}
+			return nil, errors.NewInternalError(err)
+		}
+		page := struct {
+			Policies      []IndexPolicy `json:"policies"`
+			TotalPolicies int           `json:"total_policies"`
+		}{}
+		err = json.NewDecoder(resp.Body).Decode(&page)
+		_ = resp.Body.Close()
+		if resp.StatusCode >= 300 {
+			return nil, errors.NewInternalError(fmt.Errorf("failed to list policies: %d", resp.StatusCode))
+		}
+		if err != nil {
+			return nil, errors.NewInternalError(err)
+		}
+		policies = append(policies, page.Policies...)
+		if len(page.Policies) < listPageSize || len(policies) >= page.TotalPolicies {
+			break
+		}
+	}
+	sort.Slice(policies, func(i, j int) bool { return policies[i].ID < policies[j].ID })
+	return policies, nil
+}
+
+// ImportPolicy converts a policy document stored in OpenSearch to the API type.
+// It returns the paths of the fields the API type cannot represent, which are
+// dropped. Fields maintained by OpenSearch and default retries are not reported.
+func ImportPolicy(document json.RawMessage) (*apiv1.OpensearchIndexPolicy, []string, error) {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(document, &raw); err != nil {
+		return nil, nil, fmt.Errorf("failed to decode index policy: %w", err)
+	}
+	var dropped []string
+	for _, field := range serverFields {
+		delete(raw, field)
+	}
+	// The ISM template is a list in OpenSearch but a single object in the spec.
+	if templates, ok := raw["ism_template"].([]interface{}); ok {
+		delete(raw, "ism_template")
+		for i, template := range templates {
+			if i > 0 {
+				dropped = append(dropped, fmt.Sprintf("ism_template[%d]", i))
+				continue
+			}
+			if t, ok := template.(map[string]interface{}); ok {
+				delete(t, "last_updated_time")
+				raw["ism_template"] = t
+			}
+		}
+	}
+	// error_notification is a map of strings in the spec, while OpenSearch nests destinations.
+	if notification, ok := raw["error_notification"].(map[string]interface{}); ok {
+		for _, value := range notification {
+			if _, ok := value.(string); !ok {
+				dropped = append(dropped, "error_notification")
+				delete(raw, "error_notification")
+				break
+			}
+		}
+	}
+	states, _ := raw["states"].([]interface{})
+	for i, state := range states {
+		s, _ := state.(map[string]interface{})
+		actions, _ := s["actions"].([]interface{})
+		for _, action := range actions {
+			if a, ok := action.(map[string]interface{}); ok && reflect.DeepEqual(a["retry"], defaultRetry) {
+				delete(a, "retry")
+			}
+		}
+		transitions, _ := s["transitions"].([]interface{})
+		for j, transition := range transitions {
+			t, _ := transition.(map[string]interface{})
+			conditions, _ := t["conditions"].(map[string]interface{})
+			// Conditions are strings in the spec. Numbers convert, objects such as cron do not.
+			for name, value := range conditions {
+				switch v := value.(type) {
+				case float64:
+					conditions[name] = strconv.FormatFloat(v, 'f', -1, 64)
+				case string:
+				default:
+					dropped = append(dropped, fmt.Sprintf("states[%d].transitions[%d].conditions.%s", i, j, name))
+					delete(conditions, name)
+				}
+			}
+		}
+	}
+
+	cleaned, err := json.Marshal(raw)
+	if err != nil {
+		return nil, nil, err
+	}
+	policy := &apiv1.OpensearchIndexPolicy{}
+	if err := json.Unmarshal(cleaned, policy); err != nil {
+		return nil, nil, fmt.Errorf("failed to convert index policy: %w", err)
+	}
+	imported, err := json.Marshal(policy)
+	if err != nil {
+		return nil, nil, err
+	}
+	var kept interface{}
+	if err := json.Unmarshal(imported, &kept); err != nil {
+		return nil, nil, err
+	}
+	lostFields("", raw, kept, &dropped)
+	sort.Strings(dropped)
+	return policy, dropped, nil
+}
+
+// lostFields appends the paths of the values of have missing from kept. Zero
+// values and empty lists or objects are omitted by the API type, so they are not lost.
+func lostFields(path string, have, kept interface{}, lost *[]string) {
+	switch h := have.(type) {
+	case map[string]interface{}:
+		k, _ := kept.(map[string]interface{})
+		for name, value := range h {
+			child := name
+			if path != "" {
+				child = path + "." + name
+			}
+			keptValue, ok := k[name]
+			if !ok {
+				if !isZeroJSON(value) {
+					*lost = append(*lost, child)
+				}
+				continue
+			}
+			lostFields(child, value, keptValue, lost)
+		}
+	case []interface{}:
+		k, _ := kept.([]interface{})
+		for i, value := range h {
+			if i >= len(k) {
+				*lost = append(*lost, fmt.Sprintf("%s[%d]", path, i))
+				continue
+			}
+			lostFields(fmt.Sprintf("%s[%d]", path, i), value, k[i], lost)
+		}
+	default:
+		if !isZeroJSON(have) && !scalarEqual(have, kept) {
+			*lost = append(*lost, pathOrRoot(path))
+		}
+	}
+}
+
+func isZeroJSON(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case bool:
+		return !v
+	case float64:
+		return v == 0
+	case string:
+		return v == ""
+	case []interface{}:
+		return len(v) == 0
+	case map[string]interface{}:
+		return len(v) == 0
+	}
+	return false
+}
@@ -0,0 +1,81 @@
+package opensearch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	apiv1 "github.com/a8uhnf/opensearch-ism-crd/api/v1"
+)
+
+var _ = Describe("Policy import", func() {
+	It("lists all the policies of the cluster, page by page", func() {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/" {
+				_, _ = w.Write([]byte(`{"version":{"distribution":"opensearch","number":"2.11.0"}}`))
+				return
+			}
+			from, _ := strconv.Atoi(r.URL.Query().Get("from"))
+			var policies []IndexPolicy
+			for i := from; i < min(from+listPageSize, 150); i++ {
+				policies = append(policies, IndexPolicy{ID: fmt.Sprintf("policy-%03d", i), Policy: json.RawMessage(`{}`)})
+			}
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"policies": policies, "total_policies": 150})
+		}))
+		defer server.Close()
+		client, err := NewOpenSearchClient(context.Background(), OpenSearchConfig{URL: server.URL})
+		Expect(err).NotTo(HaveOccurred())
+
+		policies, err := client.ListIndexPolicies(context.Background())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(policies).To(HaveLen(150))
+		Expect(policies[149].ID).To(Equal("policy-149"))
+	})
+
+	It("converts a stored policy and reports the fields it drops", func() {
+		policy, dropped, err := ImportPolicy(json.RawMessage(`{
+			"policy_id": "logs",
+			"description": "Rotate logs",
+			"last_updated_time": 1700000000000,
+			"schema_version": 19,
+			"error_notification": {"destination": {"slack": {"url": "https://hooks.slack.com"}}},
+			"default_state": "hot",
+			"states": [
+				{
+					"name": "hot",
+					"actions": [
+						{"retry": {"count": 3, "backoff": "exponential", "delay": "1m"}, "rollover": {"min_doc_count": 1000, "copy_alias": false}},
+						{"retry": {"count": 5, "backoff": "constant", "delay": "1m"}, "timeout": "1h", "read_only": {}}
+					],
+					"transitions": [
+						{"state_name": "delete", "conditions": {"min_doc_count": 5000}},
+						{"state_name": "delete", "conditions": {"cron": {"cron": {"expression": "0 0 * * *", "timezone": "UTC"}}}}
+					]
+				},
+				{"name": "delete", "actions": [{"delete": {}}], "transitions": []}
+			],
+			"ism_template": [
+				{"index_patterns": ["logs-*"], "priority": 100, "last_updated_time": 1700000000000},
+				{"index_patterns": ["audit-*"], "priority": 50}
+			]
+		}`))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(dropped).To(Equal([]string{
+			"error_notification",
+			"ism_template[1]",
+			"states[0].actions[1].retry",
+			"states[0].actions[1].timeout",
+			"states[0].transitions[1].conditions.cron",
+		}))
+		Expect(policy.Description).To(Equal("Rotate logs"))
+		Expect(policy.ISMTemplate).To(Equal(&apiv1.ISMTemplate{IndexPatterns: []string{"logs-*"}, Priority: 100}))
+		Expect(policy.States[0].Actions[0].RollOver.MinDocCount).To(Equal(1000))
+		Expect(policy.States[0].Transitions[0].Conditions).To(Equal(map[string]string{"min_doc_count": "5000"}))
+	})
+})
@@ -2,6 +2,10 @@ package opensearch
 
 import (
 	"context"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"strings"
 
 	apiv1 "github.com/a8uhnf/opensearch-ism-crd/api/v1"
 	"github.com/opensearch-project/opensearch-go"
@@ -12,12 +16,29 @@ type OpenSearch interface {
 	// CreateIndexPolicy creates an index policy in OpenSearch.
 	CreateIndexPolicy(ctx context.Context, policyName string, policy *apiv1.OpensearchIndexPolicy) error
 	// GetIndexPolicy retrieves an index policy from OpenSearch.
-	GetIndexPolicy(ctx context.Context, policyName string) (*apiv1.OpensearchIndexPolicy, error)
+	GetIndexPolicy(ctx context.Context, policyName string) (*IndexPolicy, error)
+	// ListIndexPolicies returns all the ISM policies of the cluster.
+	ListIndexPolicies(ctx context.Context) ([]IndexPolicy, error)
+	// UpdateIndexPolicy replaces the index policy last read with the given sequence number and primary term.
+	UpdateIndexPolicy(ctx context.Context, policyName string, seqNo, primaryTerm int64, policy *apiv1.OpensearchIndexPolicy) (*IndexPolicy, error)
+	// ExplainPolicy returns the indices managed by an index policy, among those matching the
+	// index patterns, or among all the managed indices of the cluster without patterns.
+	ExplainPolicy(ctx context.Context, policyName string, indexPatterns []string) ([]ManagedIndex, error)
+	// RetryManagedIndex retries the failed ISM action of an index.
+	RetryManagedIndex(ctx context.Context, index string) error
+	// ChangePolicy switches managed indices to another policy, or to the current version of theirs.
+	ChangePolicy(ctx context.Context, indices []string, change ChangePolicyRequest) (*ChangePolicyResult, error)
+	// UnmanagedIndices lists the indices matching the patterns that no ISM policy manages.
+	UnmanagedIndices(ctx context.Context, patterns []string) ([]string, error)
+	// AddPolicy attaches a policy to unmanaged indices.
+	AddPolicy(ctx context.Context, indices []string, policyID string) (*ChangePolicyResult, error)
 	// DeleteIndexPolicy deletes an index policy from OpenSearch.
 	DeleteIndexPolicy(ctx context.Context, policyName string) error
 	// // GetIndexPolicies retrieves all index policies from OpenSearch.
 	// GetIndexPolicies(ctx context.Context) ([]OpensearchIndexPolicy, error)
 	GetClusterHealth(ctx context.Context) (string, error)
+	// ClusterInfo returns the distribution and version of the cluster, detected once per client.
+	ClusterInfo(ctx context.Context) (ClusterInfo, error)
 }
 
 func NewOpenSearchClient(ctx context.Context, config OpenSearchConfig) (OpenSearch, error) {
@@ -27,16 +48,87 @@ func NewOpenSearchClient(ctx context.Context, config OpenSearchConfig) (OpenSear
 	// Implementation of OpenSearch client creation
 	// This would typically involve setting up a connection to the OpenSearch cluster
 	// using the provided configuration.
-	oCli, err := opensearch.NewClient(opensearch.Config{
-		Addresses: []string{config.URL},
+	osConfig := opensearch.Config{
+		Addresses: append([]string{config.URL}, config.Addresses...),
 		Username:  config.Username,
 		Password:  config.Password,
-	})
+		// Retries are handled by openSearchClient.perform so that only
+		// idempotent calls are repeated, with backoff.
+		DisableRetry: true,
+	}
+	if modes := config.authModes(); len(modes) > 1 {
+		return nil, fmt.Errorf("only one authentication mode may be set, got %s", strings.Join(modes, ", "))
+	}
+	if config.TLSConfig != nil {
+		osConfig.Transport = config.TLSConfig
+	}
+	switch {
+	case config.BearerToken != "":
+		osConfig.Header = http.Header{"Authorization": {"Bearer " + config.BearerToken}}
+	case config.APIKey != "":
+		osConfig.Header = http.Header{"Authorization": {"ApiKey " + config.APIKey}}
+	case config.ClientCertificate != nil:
+		transport := config.TLSConfig
+		if transport == nil {
+			transport = http.DefaultTransport.(*http.Transport).Clone()
+		}
+		if transport.TLSClientConfig == nil {
+			transport.TLSClientConfig = &tls.Config{}
+		} else {
+			transport.TLSClientConfig = transport.TLSClientConfig.Clone()
+		}
+		transport.TLSClientConfig.Certificates = []tls.Certificate{*config.ClientCertificate}
+		osConfig.Transport = transport
+	case config.AWS != nil:
+		if config.AWS.Credentials == nil {
+			return nil, fmt.Errorf("aws credentials provider must be set")
+		}
+		osConfig.Transport = newSigV4Transport(osConfig.Transport, *config.AWS)
+	}
+	oCli, err := opensearch.NewClient(osConfig)
 	if err != nil {
 		return nil, err
 	}
-	return &openSearchClient{
-		client: oCli,
-		url:    config.URL,
-	}, nil
+	client := &openSearchClient{
+		client:  oCli,
+		url:     config.URL,
+		retry:   config.Retry,
+		breaker: config.Breaker,
+	}
+	if config.DiscoverNodesOnStart {
+		// Discover in the background, as the client does, so that the cache is not held up.
+		go func() {
+			if err := oCli.DiscoverNodes(); err != nil {
+				logr.Error(err, "Failed to discover OpenSearch nodes, using the configured ones", "url", config.URL)
+			}
+		}()
+	}
+	// The client's own DiscoverNodesInterval cannot be stopped, so evicted
+	// clients would keep polling the cluster forever.
+	if config.DiscoverNodesInterval > 0 {
+		client.stop = make(chan struct{})
+		go client.discoverNodes(context.WithoutCancel(ctx), config.DiscoverNodesInterval)
+	}
+	return client, nil
+}
+
+// authModes returns the names of the authentication modes set on the config.
+func (c OpenSearchConfig) authModes() []string {
+	var modes []string
+	if c.Username != "" || c.Password != "" {
+		modes = append(modes, "basic")
+	}
+	if c.BearerToken != "" {
+		modes = append(modes, "bearer token")
+	}
+	if c.APIKey != "" {
+		modes = append(modes, "api key")
+	}
+	if c.ClientCertificate != nil {
+		modes = append(modes, "client certificate")
+	}
+	if c.AWS != nil {
+		modes = append(modes, "aws")
+	}
+	return modes
 }
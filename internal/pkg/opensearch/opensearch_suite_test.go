@@ -0,0 +1,14 @@
+package opensearch
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestOpenSearch(t *testing.T) {
+	RegisterFailHandler(Fail)
+
+	RunSpecs(t, "OpenSearch Suite")
+}
@@ -0,0 +1,43 @@
+package opensearch
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	apiv1 "github.com/a8uhnf/opensearch-ism-crd/api/v1"
+)
+
+// ISM policies carry no metadata of their own, so the object managing a policy
+// is recorded at the end of its description, e.g.
+// "Rotate logs [managed-by osindexpolicy default/logs]". Owners without a
+// namespace are ClusterOSIndexPolicies, e.g. "[managed-by clusterosindexpolicy logs]".
+var ownerMarker = regexp.MustCompile(`\s*\[managed-by (?:cluster)?osindexpolicy ([^\]]+)\]$`)
+
+// WithOwner returns a copy of the policy whose description names the owner.
+func WithOwner(policy *apiv1.OpensearchIndexPolicy, owner string) *apiv1.OpensearchIndexPolicy {
+	owned := policy.DeepCopy()
+	description := ownerMarker.ReplaceAllString(owned.Description, "")
+	kind := "osindexpolicy"
+	if !strings.Contains(owner, "/") {
+		kind = "clusterosindexpolicy"
+	}
+	owned.Description = strings.TrimSpace(fmt.Sprintf("%s [managed-by %s %s]", description, kind, owner))
+	return owned
+}
+
+// PolicyOwner returns the owner recorded in the description of a policy
+// document stored in OpenSearch, empty when there is none.
+func PolicyOwner(policy json.RawMessage) string {
+	document := struct {
+		Description string `json:"description"`
+	}{}
+	if err := json.Unmarshal(policy, &document); err != nil {
+		return ""
+	}
+	if match := ownerMarker.FindStringSubmatch(document.Description); match != nil {
+		return match[1]
+	}
+	return ""
+}
@@ -0,0 +1,39 @@
+package opensearch
+
+import (
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	apiv1 "github.com/a8uhnf/opensearch-ism-crd/api/v1"
+)
+
+var _ = Describe("Policy ownership", func() {
+	It("records the owner at the end of the description", func() {
+		owned := WithOwner(&apiv1.OpensearchIndexPolicy{Description: "Rotate logs"}, "default/logs")
+		Expect(owned.Description).To(Equal("Rotate logs [managed-by osindexpolicy default/logs]"))
+
+		raw, err := json.Marshal(owned)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(PolicyOwner(raw)).To(Equal("default/logs"))
+	})
+
+	It("replaces a previous owner", func() {
+		owned := WithOwner(&apiv1.OpensearchIndexPolicy{Description: "Rotate logs [managed-by osindexpolicy team-a/logs]"}, "team-b/logs")
+		Expect(owned.Description).To(Equal("Rotate logs [managed-by osindexpolicy team-b/logs]"))
+	})
+
+	It("records cluster-scoped owners by name", func() {
+		owned := WithOwner(&apiv1.OpensearchIndexPolicy{Description: "Rotate logs [managed-by osindexpolicy default/logs]"}, "logs")
+		Expect(owned.Description).To(Equal("Rotate logs [managed-by clusterosindexpolicy logs]"))
+
+		raw, err := json.Marshal(owned)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(PolicyOwner(raw)).To(Equal("logs"))
+	})
+
+	It("reports policies made by hand as unowned", func() {
+		Expect(PolicyOwner(json.RawMessage(`{"description":"Rotate logs"}`))).To(BeEmpty())
+	})
+})
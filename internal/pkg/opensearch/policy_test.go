@@ -0,0 +1,201 @@
+package opensearch
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"k8s.io/apimachinery/pkg/api/errors"
+
+	apiv1 "github.com/a8uhnf/opensearch-ism-crd/api/v1"
+	"github.com/a8uhnf/opensearch-ism-crd/internal/metrics"
+)
+
+var _ = Describe("Policy API", func() {
+	var (
+		ctx      context.Context
+		server   *httptest.Server
+		client   OpenSearch
+		requests []string
+		bodies   []string
+		seqNo    string
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		requests, bodies = nil, nil
+		seqNo = "7"
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			requests = append(requests, r.Method+" "+r.URL.RequestURI())
+			bodies = append(bodies, string(body))
+			switch {
+			case r.URL.Path == "/":
+				_, _ = w.Write([]byte(`{"version":{"distribution":"opensearch","number":"2.11.0"}}`))
+			case r.Method == http.MethodGet && r.URL.Path == "/_plugins/_ism/policies/logs":
+				_, _ = w.Write([]byte(`{"_id":"logs","_seq_no":7,"_primary_term":2,"policy":{"policy_id":"logs","description":"logs"}}`))
+			case r.Method == http.MethodPut && r.URL.Query().Get("if_seq_no") != seqNo:
+				w.WriteHeader(http.StatusConflict)
+			case r.Method == http.MethodPut:
+				_, _ = w.Write([]byte(`{"_id":"logs","_seq_no":8,"_primary_term":2,"policy":{"policy_id":"logs","description":"logs"}}`))
+			case strings.HasPrefix(r.URL.Path, "/_plugins/_ism/change_policy/"):
+				_, _ = w.Write([]byte(`{"updated_indices":1,"failures":true,"failed_indices":[{"index_name":"logs-000001","reason":"Index is not managed"}]}`))
+			case r.URL.Path == "/_plugins/_ism/retry/logs-000002":
+				_, _ = w.Write([]byte(`{"updated_indices":1,"failures":false,"failed_indices":[]}`))
+			case r.URL.Path == "/_plugins/_ism/retry/logs-000001":
+				_, _ = w.Write([]byte(`{"updated_indices":0,"failures":true,"failed_indices":[{"index_name":"logs-000001","reason":"This index is not in failed state."}]}`))
+			case r.URL.Path == "/_plugins/_ism/explain/logs-*,old-logs":
+				_, _ = w.Write([]byte(`{
+					"logs-000003": {"index.plugins.index_state_management.policy_id": null, "index.opendistro.index_state_management.policy_id": null, "enabled": null},
+					"logs-000001": {"index.plugins.index_state_management.policy_id": "logs", "index": "logs-000001", "policy_id": "logs", "enabled": true},
+					"old-logs": {"index.plugins.index_state_management.policy_id": null, "index.opendistro.index_state_management.policy_id": null, "enabled": null},
+					"total_managed_indices": 1
+				}`))
+			case strings.HasPrefix(r.URL.Path, "/_plugins/_ism/add/"):
+				_, _ = w.Write([]byte(`{"updated_indices":2,"failures":false,"failed_indices":[]}`))
+			case r.URL.Path == "/_plugins/_ism/explain":
+				_, _ = w.Write([]byte(`{
+					"logs-000002": {"index": "logs-000002", "policy_id": "logs",
+						"state": {"name": "hot", "start_time": 1700000000000},
+						"action": {"name": "rollover", "failed": true},
+						"info": {"message": "Missing rollover_alias"}},
+					"logs-000001": {"index": "logs-000001", "policy_id": "logs",
+						"state": {"name": "delete", "start_time": 1600000000000},
+						"action": {"name": "delete", "failed": false},
+						"info": {}},
+					"metrics-000001": {"index": "metrics-000001", "policy_id": "metrics"},
+					"total_managed_indices": 3
+				}`))
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+		var err error
+		client, err = NewOpenSearchClient(ctx, OpenSearchConfig{URL: server.URL})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("reads the policy with its version", func() {
+		policy, err := client.GetIndexPolicy(ctx, "logs")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(policy.ID).To(Equal("logs"))
+		Expect(policy.SeqNo).To(Equal(int64(7)))
+		Expect(policy.PrimaryTerm).To(Equal(int64(2)))
+		Expect(string(policy.Policy)).To(Equal(`{"policy_id":"logs","description":"logs"}`))
+	})
+
+	It("reports missing policies as not found", func() {
+		_, err := client.GetIndexPolicy(ctx, "missing")
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("updates the policy it read", func() {
+		updated, err := client.UpdateIndexPolicy(ctx, "logs", 7, 2, &apiv1.OpensearchIndexPolicy{Description: "logs"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(updated.SeqNo).To(Equal(int64(8)))
+		Expect(requests).To(ContainElement("PUT /_plugins/_ism/policies/logs?if_seq_no=7&if_primary_term=2"))
+		Expect(bodies[len(bodies)-1]).To(MatchJSON(`{"policy":{"description":"logs"}}`))
+	})
+
+	It("reports concurrent updates as conflicts", func() {
+		seqNo = "8"
+		_, err := client.UpdateIndexPolicy(ctx, "logs", 7, 2, &apiv1.OpensearchIndexPolicy{Description: "logs"})
+		Expect(errors.IsConflict(err)).To(BeTrue())
+	})
+
+	It("explains the indices managed by the policy", func() {
+		indices, err := client.ExplainPolicy(ctx, "logs", nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(indices).To(Equal([]ManagedIndex{
+			{
+				Index:          "logs-000001",
+				PolicyID:       "logs",
+				State:          "delete",
+				StateStartTime: time.UnixMilli(1600000000000).UTC(),
+				Action:         "delete",
+			},
+			{
+				Index:          "logs-000002",
+				PolicyID:       "logs",
+				State:          "hot",
+				StateStartTime: time.UnixMilli(1700000000000).UTC(),
+				Action:         "rollover",
+				Failed:         true,
+				Message:        "Missing rollover_alias",
+			},
+		}))
+		Expect(requests).To(ContainElement("GET /_plugins/_ism/explain?from=0&size=1000"))
+	})
+
+	It("explains only the indices matching the index patterns", func() {
+		indices, err := client.ExplainPolicy(ctx, "logs", []string{"logs-*", "old-logs"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(indices).To(Equal([]ManagedIndex{{Index: "logs-000001", PolicyID: "logs"}}))
+		Expect(requests).To(ContainElement("GET /_plugins/_ism/explain/logs-*,old-logs"))
+		Expect(requests).NotTo(ContainElement(HavePrefix("GET /_plugins/_ism/explain?")))
+
+		indices, err = client.ExplainPolicy(ctx, "logs", []string{"missing"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(indices).To(BeEmpty())
+	})
+
+	It("retries failed managed indices", func() {
+		Expect(client.RetryManagedIndex(ctx, "logs-000002")).To(Succeed())
+		Expect(requests).To(ContainElement("POST /_plugins/_ism/retry/logs-000002"))
+
+		Expect(client.RetryManagedIndex(ctx, "logs-000001")).To(MatchError(ContainSubstring("not in failed state")))
+	})
+
+	It("changes the policy of managed indices in batches", func() {
+		indices := make([]string, 150)
+		for i := range indices {
+			indices[i] = fmt.Sprintf("logs-%06d", i)
+		}
+		result, err := client.ChangePolicy(ctx, indices, ChangePolicyRequest{
+			PolicyID: "logs",
+			State:    "warm",
+			Include:  []ChangePolicyInclude{{State: "hot"}},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.UpdatedIndices).To(Equal(2))
+		Expect(result.FailedIndices).To(HaveLen(2))
+		Expect(requests).To(ContainElements(
+			"POST /_plugins/_ism/change_policy/"+strings.Join(indices[:100], ","),
+			"POST /_plugins/_ism/change_policy/"+strings.Join(indices[100:], ","),
+		))
+		Expect(bodies[len(bodies)-1]).To(MatchJSON(`{"policy_id":"logs","state":"warm","include":[{"state":"hot"}]}`))
+	})
+
+	It("lists the unmanaged indices matching the patterns", func() {
+		indices, err := client.UnmanagedIndices(ctx, []string{"logs-*", "old-logs"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(indices).To(Equal([]string{"logs-000003", "old-logs"}))
+	})
+
+	It("attaches the policy to existing indices", func() {
+		result, err := client.AddPolicy(ctx, []string{"logs-000003", "old-logs"}, "logs")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.UpdatedIndices).To(Equal(2))
+		Expect(requests).To(ContainElement("POST /_plugins/_ism/add/logs-000003,old-logs"))
+		Expect(bodies[len(bodies)-1]).To(MatchJSON(`{"policy_id":"logs"}`))
+	})
+
+	It("observes request latency per endpoint, method and status", func() {
+		_, _ = client.GetIndexPolicy(ctx, "missing")
+		observed := &dto.Metric{}
+		Expect(metrics.RequestDuration.WithLabelValues(server.URL, http.MethodGet, "404").(prometheus.Metric).Write(observed)).To(Succeed())
+		Expect(observed.GetHistogram().GetSampleCount()).To(Equal(uint64(1)))
+	})
+})
@@ -0,0 +1,93 @@
+package opensearch
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/errors"
+
+	apiv1 "github.com/a8uhnf/opensearch-ism-crd/api/v1"
+)
+
+var _ = Describe("Retries", func() {
+	var (
+		ctx      context.Context
+		server   *httptest.Server
+		calls    atomic.Int32
+		failures int32
+		client   OpenSearch
+		breaker  *CircuitBreaker
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		calls.Store(0)
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/" {
+				_, _ = w.Write([]byte(`{"version":{"distribution":"opensearch","number":"2.11.0"}}`))
+				return
+			}
+			if calls.Add(1) <= failures {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(`{}`))
+		}))
+		breaker = NewCircuitBreaker(server.URL, BreakerConfig{FailureThreshold: 1, OpenDuration: time.Minute})
+		var err error
+		client, err = NewOpenSearchClient(ctx, OpenSearchConfig{
+			URL:     server.URL,
+			Retry:   RetryConfig{MaxRetries: 2, InitialBackoff: time.Millisecond, MaxBackoff: 5 * time.Millisecond},
+			Breaker: breaker,
+		})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("should retry idempotent calls on transient errors", func() {
+		failures = 2
+		_, err := client.GetIndexPolicy(ctx, "p")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(calls.Load()).To(BeEquivalentTo(3))
+		Expect(breaker.Allow()).To(Succeed())
+	})
+
+	It("should not retry non-idempotent calls", func() {
+		failures = 1
+		err := client.CreateIndexPolicy(ctx, "p", &apiv1.OpensearchIndexPolicy{})
+		Expect(errors.IsInternalError(err)).To(BeTrue())
+		Expect(calls.Load()).To(BeEquivalentTo(1))
+	})
+
+	It("should open the breaker once retries are exhausted", func() {
+		failures = 10
+		_, err := client.GetIndexPolicy(ctx, "p")
+		Expect(err).To(HaveOccurred())
+		Expect(calls.Load()).To(BeEquivalentTo(3))
+
+		_, err = client.GetIndexPolicy(ctx, "p")
+		_, open := IsCircuitOpen(err)
+		Expect(open).To(BeTrue())
+		Expect(calls.Load()).To(BeEquivalentTo(3))
+	})
+})
+
+var _ = Describe("RetryConfig", func() {
+	It("should back off exponentially with jitter up to the maximum", func() {
+		r := RetryConfig{InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+		for range 20 {
+			Expect(r.backoff(0)).To(BeNumerically("~", 75*time.Millisecond, 25*time.Millisecond))
+			Expect(r.backoff(2)).To(BeNumerically("~", 300*time.Millisecond, 100*time.Millisecond))
+			Expect(r.backoff(10)).To(BeNumerically("~", 750*time.Millisecond, 250*time.Millisecond))
+		}
+	})
+})
@@ -0,0 +1,347 @@
+package opensearch
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// AWS services that accept SigV4 signed OpenSearch requests.
+const (
+	// AWSServiceES is Amazon OpenSearch Service managed domains.
+	AWSServiceES = "es"
+	// AWSServiceAOSS is Amazon OpenSearch Serverless collections.
+	AWSServiceAOSS = "aoss"
+)
+
+const (
+	sigV4Algorithm  = "AWS4-HMAC-SHA256"
+	sigV4TimeFormat = "20060102T150405Z"
+	sigV4DateFormat = "20060102"
+
+	headerAmzDate          = "X-Amz-Date"
+	headerAmzContentSHA256 = "X-Amz-Content-Sha256"
+	headerAmzSecurityToken = "X-Amz-Security-Token"
+)
+
+// AWSConfig configures AWS Signature Version 4 request signing.
+type AWSConfig struct {
+	// Region of the domain or collection, e.g. "eu-west-1".
+	Region string
+	// Service is AWSServiceES or AWSServiceAOSS.
+	Service string
+	// Credentials provides the signing credentials.
+	Credentials AWSCredentialsProvider
+}
+
+// AWSCredentials are the credentials used to sign a request.
+type AWSCredentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+	// Expires is when temporary credentials expire, zero for long-lived ones.
+	Expires time.Time
+}
+
+// AWSCredentialsProvider returns the current AWS credentials.
+type AWSCredentialsProvider interface {
+	Retrieve(ctx context.Context) (AWSCredentials, error)
+}
+
+// StaticAWSCredentials is an AWSCredentialsProvider returning fixed credentials.
+type StaticAWSCredentials AWSCredentials
+
+// Retrieve implements AWSCredentialsProvider.
+func (s StaticAWSCredentials) Retrieve(_ context.Context) (AWSCredentials, error) {
+	if s.AccessKeyID == "" || s.SecretAccessKey == "" {
+		return AWSCredentials{}, fmt.Errorf("aws access key id and secret access key must be set")
+	}
+	return AWSCredentials(s), nil
+}
+
+// WebIdentityCredentials exchanges a web identity token, such as the projected
+// service account token of IRSA, for temporary credentials using STS
+// AssumeRoleWithWebIdentity. Credentials are cached until shortly before they expire.
+type WebIdentityCredentials struct {
+	// RoleARN is the role to assume.
+	RoleARN string
+	// TokenFile is the path of the web identity token.
+	TokenFile string
+	// SessionName names the assumed role session.
+	SessionName string
+	// Endpoint is the STS endpoint, e.g. "https://sts.eu-west-1.amazonaws.com".
+	Endpoint string
+	// HTTPClient sends the STS request, http.DefaultClient when nil.
+	HTTPClient *http.Client
+
+	mu     sync.Mutex
+	cached AWSCredentials
+	now    func() time.Time
+}
+
+// NewWebIdentityCredentialsFromEnv returns a WebIdentityCredentials configured
+// from the environment variables injected by IRSA. roleARN overrides AWS_ROLE_ARN.
+func NewWebIdentityCredentialsFromEnv(region, roleARN string) (*WebIdentityCredentials, error) {
+	if roleARN == "" {
+		roleARN = os.Getenv("AWS_ROLE_ARN")
+	}
+	tokenFile := os.Getenv("AWS_WEB_IDENTITY_TOKEN_FILE")
+	if roleARN == "" || tokenFile == "" {
+		return nil, fmt.Errorf("AWS_ROLE_ARN and AWS_WEB_IDENTITY_TOKEN_FILE must be set to use web identity credentials")
+	}
+	sessionName := os.Getenv("AWS_ROLE_SESSION_NAME")
+	if sessionName == "" {
+		sessionName = "opensearch-ism-crd"
+	}
+	return &WebIdentityCredentials{
+		RoleARN:     roleARN,
+		TokenFile:   tokenFile,
+		SessionName: sessionName,
+		Endpoint:    fmt.Sprintf("https://sts.%s.amazonaws.com", region),
+	}, nil
+}
+
+// Retrieve implements AWSCredentialsProvider.
+func (w *WebIdentityCredentials) Retrieve(ctx context.Context) (AWSCredentials, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	now := time.Now
+	if w.now != nil {
+		now = w.now
+	}
+	if w.cached.AccessKeyID != "" && now().Add(5*time.Minute).Before(w.cached.Expires) {
+		return w.cached, nil
+	}
+
+	token, err := os.ReadFile(w.TokenFile)
+	if err != nil {
+		return AWSCredentials{}, fmt.Errorf("failed to read web identity token: %w", err)
+	}
+	query := url.Values{
+		"Action":           {"AssumeRoleWithWebIdentity"},
+		"Version":          {"2011-06-15"},
+		"RoleArn":          {w.RoleARN},
+		"RoleSessionName":  {w.SessionName},
+		"WebIdentityToken": {strings.TrimSpace(string(token))},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.Endpoint,
+		strings.NewReader(query.Encode()))
+	if err != nil {
+		return AWSCredentials{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	httpClient := w.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return AWSCredentials{}, fmt.Errorf("failed to assume role with web identity: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(resp.Body)
+		return AWSCredentials{}, fmt.Errorf("failed to assume role with web identity: %d: %s", resp.StatusCode, body)
+	}
+
+	var out struct {
+		Credentials struct {
+			AccessKeyID     string    `xml:"AccessKeyId"`
+			SecretAccessKey string    `xml:"SecretAccessKey"`
+			SessionToken    string    `xml:"SessionToken"`
+			Expiration      time.Time `xml:"Expiration"`
+		} `xml:"AssumeRoleWithWebIdentityResult>Credentials"`
+	}
+	if err := xml.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return AWSCredentials{}, fmt.Errorf("failed to decode web identity credentials: %w", err)
+	}
+	w.cached = AWSCredentials{
+		AccessKeyID:     out.Credentials.AccessKeyID,
+		SecretAccessKey: out.Credentials.SecretAccessKey,
+		SessionToken:    out.Credentials.SessionToken,
+		Expires:         out.Credentials.Expiration,
+	}
+	return w.cached, nil
+}
+
+// sigV4Transport signs every request with AWS Signature Version 4 before
+// handing it to the wrapped transport.
+type sigV4Transport struct {
+	base   http.RoundTripper
+	config AWSConfig
+	now    func() time.Time
+}
+
+func newSigV4Transport(base http.RoundTripper, config AWSConfig) *sigV4Transport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &sigV4Transport{base: base, config: config, now: time.Now}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *sigV4Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	creds, err := t.config.Credentials.Retrieve(req.Context())
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve aws credentials: %w", err)
+	}
+
+	// Never mutate the caller's request, see http.RoundTripper.
+	signed := req.Clone(req.Context())
+	var body []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		body, err = io.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		signed.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	signRequest(signed, body, creds, t.config.Region, t.config.Service, t.now().UTC())
+	return t.base.RoundTrip(signed)
+}
+
+// CloseIdleConnections closes the idle connections of the wrapped transport.
+func (t *sigV4Transport) CloseIdleConnections() {
+	if c, ok := t.base.(interface{ CloseIdleConnections() }); ok {
+		c.CloseIdleConnections()
+	}
+}
+
+// signRequest adds the SigV4 headers for the request, with the given body, to req.
+func signRequest(req *http.Request, body []byte, creds AWSCredentials, region, service string, now time.Time) {
+	// Basic auth set by the OpenSearch client would replace the signature.
+	req.Header.Del("Authorization")
+	req.Header.Set(headerAmzDate, now.Format(sigV4TimeFormat))
+	payloadHash := sha256Hex(body)
+	if service == AWSServiceAOSS {
+		// Serverless requires the payload hash to be sent, and signed.
+		req.Header.Set(headerAmzContentSHA256, payloadHash)
+	}
+	if creds.SessionToken != "" {
+		req.Header.Set(headerAmzSecurityToken, creds.SessionToken)
+	}
+
+	scope := strings.Join([]string{now.Format(sigV4DateFormat), region, service, "aws4_request"}, "/")
+	canonical, signedHeaders := canonicalRequest(req, payloadHash)
+	signature := sigV4Signature(creds.SecretAccessKey, now, region, service, scope, canonical)
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		sigV4Algorithm, creds.AccessKeyID, scope, signedHeaders, signature))
+}
+
+// sigV4Signature returns the hex signature of a canonical request.
+func sigV4Signature(secret string, now time.Time, region, service, scope, canonical string) string {
+	stringToSign := strings.Join([]string{
+		sigV4Algorithm,
+		now.Format(sigV4TimeFormat),
+		scope,
+		sha256Hex([]byte(canonical)),
+	}, "\n")
+	key := hmacSHA256([]byte("AWS4"+secret), now.Format(sigV4DateFormat))
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, service)
+	key = hmacSHA256(key, "aws4_request")
+	return hex.EncodeToString(hmacSHA256(key, stringToSign))
+}
+
+// canonicalRequest builds the SigV4 canonical request and the list of signed headers.
+func canonicalRequest(req *http.Request, payloadHash string) (string, string) {
+	host := req.Host
+	if host == "" {
+		host = req.URL.Host
+	}
+	headers := map[string]string{"host": host}
+	for name, values := range req.Header {
+		lower := strings.ToLower(name)
+		if lower == "content-type" || strings.HasPrefix(lower, "x-amz-") {
+			headers[lower] = strings.Join(values, ",")
+		}
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + strings.Join(strings.Fields(headers[name]), " ") + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	path := req.URL.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	return strings.Join([]string{
+		req.Method,
+		uriEncode(path, false),
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n"), signedHeaders
+}
+
+func canonicalQuery(query url.Values) string {
+	encoded := make(map[string][]string, len(query))
+	keys := make([]string, 0, len(query))
+	for key, values := range query {
+		k := uriEncode(key, true)
+		keys = append(keys, k)
+		for _, v := range values {
+			encoded[k] = append(encoded[k], uriEncode(v, true))
+		}
+		sort.Strings(encoded[k])
+	}
+	sort.Strings(keys)
+	pairs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		for _, v := range encoded[k] {
+			pairs = append(pairs, k+"="+v)
+		}
+	}
+	return strings.Join(pairs, "&")
+}
+
+// uriEncode percent-encodes everything but RFC 3986 unreserved characters,
+// and slashes unless encodeSlash is set.
+func uriEncode(s string, encodeSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '.', c == '~':
+			b.WriteByte(c)
+		case c == '/' && !encodeSlash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,228 @@
+package opensearch
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	apiv1 "github.com/a8uhnf/opensearch-ism-crd/api/v1"
+)
+
+var _ = Describe("SigV4", func() {
+	// Credentials of the AWS SigV4 test suite.
+	exampleCreds := AWSCredentials{
+		AccessKeyID:     "AKIDEXAMPLE",
+		SecretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY",
+	}
+
+	// Requests and signatures of the AWS SigV4 test suite, signed for
+	// service "service" in us-east-1 at 20150830T123600Z.
+	DescribeTable("matches the AWS SigV4 test suite",
+		func(method, target, contentType, body, signedHeaders, signature string) {
+			req, err := http.NewRequest(method, "https://example.amazonaws.com"+target, strings.NewReader(body))
+			Expect(err).NotTo(HaveOccurred())
+			if contentType != "" {
+				req.Header.Set("Content-Type", contentType)
+			}
+			now := time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC)
+
+			signRequest(req, []byte(body), exampleCreds, "us-east-1", "service", now)
+
+			Expect(req.Header.Get("X-Amz-Date")).To(Equal("20150830T123600Z"))
+			Expect(req.Header.Get("Authorization")).To(Equal(
+				"AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, " +
+					"SignedHeaders=" + signedHeaders + ", Signature=" + signature))
+		},
+		Entry("get-vanilla", http.MethodGet, "/", "", "", "host;x-amz-date",
+			"5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31"),
+		Entry("get-vanilla-query-order-key-case", http.MethodGet, "/?Param2=value2&Param1=value1", "", "", "host;x-amz-date",
+			"b97d918cfa904a5beff61c982a1b6f458b799221646efd99d3219ec94cdf2500"),
+		Entry("get-vanilla-query-order-key", http.MethodGet, "/?Param1=value2&Param1=Value1", "", "", "host;x-amz-date",
+			"eedbc4e291e521cf13422ffca22be7d2eb8146eecf653089df300a15b2382bd1"),
+		Entry("get-vanilla-query-order-value", http.MethodGet, "/?Param1=value2&Param1=value1", "", "", "host;x-amz-date",
+			"5772eed61e12b33fae39ee5e7012498b51d56abc0abb7c60486157bd471c4694"),
+		Entry("get-vanilla-query-unreserved", http.MethodGet,
+			"/?-._~0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz=-._~0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz",
+			"", "", "host;x-amz-date",
+			"9c3e54bfcdf0b19771a7f523ee5669cdf59bc7cc0884027167c21bb143a40197"),
+		Entry("get-vanilla-utf8-query", http.MethodGet, "/?%E1%88%B4=bar", "", "", "host;x-amz-date",
+			"2cdec8eed098649ff3a119c94853b13c643bcf08f8b0a1d91e12c9027818dd04"),
+		Entry("post-vanilla", http.MethodPost, "/", "", "", "host;x-amz-date",
+			"5da7c1a2acd57cee7505fc6676e4e544621c30862966e37dddb68e92efbe5d6b"),
+		Entry("post-vanilla-query", http.MethodPost, "/?Param1=value1", "", "", "host;x-amz-date",
+			"28038455d6de14eafc1f9222cf5aa6f1a96197d7deb8263271d420d138af7f11"),
+		Entry("post-x-www-form-urlencoded", http.MethodPost, "/",
+			"application/x-www-form-urlencoded", "Param1=value1", "content-type;host;x-amz-date",
+			"ff11897932ad3f4e8b18135d722051e5ac45fc38421b1da7b9d196a0fe09473a"),
+		Entry("post-x-www-form-urlencoded-parameters", http.MethodPost, "/",
+			"application/x-www-form-urlencoded; charset=utf8", "Param1=value1", "content-type;host;x-amz-date",
+			"1a72ec8f64bd914b0e42e42607c7fbce7fb2c7465f63e3092b3b0d39fa77a6fe"),
+	)
+
+	DescribeTable("signs requests sent by the client",
+		func(service string, sessionToken string) {
+			var (
+				signed  atomic.Bool
+				message string
+			)
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				if msg := verifySignature(r, body, exampleCreds.SecretAccessKey, "eu-west-1", service); msg != "" {
+					message = msg
+					w.WriteHeader(http.StatusForbidden)
+					return
+				}
+				if r.Header.Get("X-Amz-Security-Token") != sessionToken {
+					message = "unexpected session token"
+					w.WriteHeader(http.StatusForbidden)
+					return
+				}
+				if r.URL.Path == "/" {
+					_, _ = w.Write([]byte(`{"version":{"distribution":"opensearch","number":"2.11.0"}}`))
+					return
+				}
+				signed.Store(true)
+				_, _ = w.Write([]byte(`{}`))
+			}))
+			defer server.Close()
+
+			creds := exampleCreds
+			creds.SessionToken = sessionToken
+			client, err := NewOpenSearchClient(context.Background(), OpenSearchConfig{
+				URL: server.URL,
+				AWS: &AWSConfig{
+					Region:      "eu-west-1",
+					Service:     service,
+					Credentials: StaticAWSCredentials(creds),
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			err = client.CreateIndexPolicy(context.Background(), "logs", &apiv1.OpensearchIndexPolicy{Description: "logs"})
+			Expect(err).NotTo(HaveOccurred(), message)
+			Expect(signed.Load()).To(BeTrue())
+		},
+		Entry("managed domain", AWSServiceES, ""),
+		Entry("serverless collection", AWSServiceAOSS, ""),
+		Entry("temporary credentials", AWSServiceES, "session-token"),
+	)
+
+	It("requires credentials", func() {
+		_, err := NewOpenSearchClient(context.Background(), OpenSearchConfig{
+			URL: "http://localhost:9200",
+			AWS: &AWSConfig{Region: "eu-west-1", Service: AWSServiceES},
+		})
+		Expect(err).To(HaveOccurred())
+	})
+
+	Describe("web identity credentials", func() {
+		var (
+			sts       *httptest.Server
+			calls     atomic.Int32
+			tokenFile string
+		)
+
+		BeforeEach(func() {
+			calls.Store(0)
+			sts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls.Add(1)
+				_ = r.ParseForm()
+				if r.Form.Get("Action") != "AssumeRoleWithWebIdentity" ||
+					r.Form.Get("RoleArn") != "arn:aws:iam::123456789012:role/ism" ||
+					r.Form.Get("WebIdentityToken") != "projected-token" {
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+				_, _ = w.Write([]byte(`<AssumeRoleWithWebIdentityResponse>
+  <AssumeRoleWithWebIdentityResult>
+    <Credentials>
+      <AccessKeyId>ASIAEXAMPLE</AccessKeyId>
+      <SecretAccessKey>secret</SecretAccessKey>
+      <SessionToken>token</SessionToken>
+      <Expiration>2030-01-01T01:00:00Z</Expiration>
+    </Credentials>
+  </AssumeRoleWithWebIdentityResult>
+</AssumeRoleWithWebIdentityResponse>`))
+			}))
+			tokenFile = filepath.Join(GinkgoT().TempDir(), "token")
+			Expect(os.WriteFile(tokenFile, []byte("projected-token\n"), 0o600)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			sts.Close()
+		})
+
+		It("assumes the role and caches the credentials until they expire", func() {
+			now := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+			provider := &WebIdentityCredentials{
+				RoleARN:     "arn:aws:iam::123456789012:role/ism",
+				TokenFile:   tokenFile,
+				SessionName: "test",
+				Endpoint:    sts.URL,
+				now:         func() time.Time { return now },
+			}
+
+			creds, err := provider.Retrieve(context.Background())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(creds.AccessKeyID).To(Equal("ASIAEXAMPLE"))
+			Expect(creds.SessionToken).To(Equal("token"))
+
+			_, err = provider.Retrieve(context.Background())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(calls.Load()).To(Equal(int32(1)))
+
+			now = now.Add(56 * time.Minute)
+			_, err = provider.Retrieve(context.Background())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(calls.Load()).To(Equal(int32(2)))
+		})
+
+		It("is configured from the IRSA environment", func() {
+			GinkgoT().Setenv("AWS_ROLE_ARN", "arn:aws:iam::123456789012:role/env")
+			GinkgoT().Setenv("AWS_WEB_IDENTITY_TOKEN_FILE", tokenFile)
+
+			provider, err := NewWebIdentityCredentialsFromEnv("eu-west-1", "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(provider.RoleARN).To(Equal("arn:aws:iam::123456789012:role/env"))
+			Expect(provider.Endpoint).To(Equal("https://sts.eu-west-1.amazonaws.com"))
+
+			provider, err = NewWebIdentityCredentialsFromEnv("eu-west-1", "arn:aws:iam::123456789012:role/spec")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(provider.RoleARN).To(Equal("arn:aws:iam::123456789012:role/spec"))
+		})
+	})
+})
+
+// verifySignature recomputes the signature of a received request and returns
+// a description of the mismatch, or "" when it is valid. It checks that what
+// the client sends is what was signed, the test suite checks the signature.
+func verifySignature(r *http.Request, body []byte, secret, region, service string) string {
+	auth := r.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, sigV4Algorithm+" ") {
+		return "not signed: " + auth
+	}
+	now, err := time.Parse(sigV4TimeFormat, r.Header.Get("X-Amz-Date"))
+	if err != nil {
+		return err.Error()
+	}
+	payloadHash := sha256Hex(body)
+	if service == AWSServiceAOSS && r.Header.Get("X-Amz-Content-Sha256") != payloadHash {
+		return "missing payload hash"
+	}
+	scope := strings.Join([]string{now.Format(sigV4DateFormat), region, service, "aws4_request"}, "/")
+	canonical, _ := canonicalRequest(r, payloadHash)
+	want := "Signature=" + sigV4Signature(secret, now, region, service, scope, canonical)
+	if !strings.HasSuffix(auth, want) {
+		return "signature mismatch: " + auth
+	}
+	return ""
+}
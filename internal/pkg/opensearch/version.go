@@ -0,0 +1,138 @@
+package opensearch
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	apiv1 "github.com/a8uhnf/opensearch-ism-crd/api/v1"
+)
+
+// Distributions reported by ClusterInfo.
+const (
+	// DistributionOpenSearch is any OpenSearch cluster.
+	DistributionOpenSearch = "opensearch"
+	// DistributionOpenDistro is Elasticsearch running the Open Distro plugins.
+	DistributionOpenDistro = "opendistro"
+)
+
+// ClusterInfo describes the distribution and version of an OpenSearch cluster.
+type ClusterInfo struct {
+	// Distribution is one of DistributionOpenSearch or DistributionOpenDistro.
+	Distribution string
+	// Version is the cluster version, e.g. "2.11.0". Empty when it could not be detected.
+	Version string
+}
+
+// ISMPrefix returns the path prefix of the ISM API on the cluster.
+func (i ClusterInfo) ISMPrefix() string {
+	if i.Distribution == DistributionOpenDistro {
+		return "_opendistro/_ism"
+	}
+	return "_plugins/_ism"
+}
+
+// actionMinVersions lists the ISM actions that only exist in OpenSearch, by
+// the first OpenSearch version that supports them. Open Distro supports none.
+var actionMinVersions = map[string]string{
+	"rollup":                  "1.0.0",
+	"shrink":                  "1.1.0",
+	"stop_replication":        "1.1.0",
+	"convert_index_to_remote": "2.19.0",
+}
+
+// SupportsAction reports whether the cluster knows the ISM action of the given name.
+// Actions are assumed supported when the version is unknown.
+func (i ClusterInfo) SupportsAction(name string) bool {
+	minVersion, ok := actionMinVersions[name]
+	if !ok {
+		return true
+	}
+	if i.Distribution == DistributionOpenDistro {
+		return false
+	}
+	if i.Version == "" {
+		return true
+	}
+	return compareVersions(i.Version, minVersion) >= 0
+}
+
+// UnsupportedActions returns, sorted, the names of the policy's actions the cluster does not support.
+func UnsupportedActions(info ClusterInfo, policy *apiv1.OpensearchIndexPolicy) []string {
+	seen := map[string]struct{}{}
+	for _, state := range policy.States {
+		if state == nil {
+			continue
+		}
+		for _, action := range state.Actions {
+			for _, name := range ActionNames(action) {
+				if !info.SupportsAction(name) {
+					seen[name] = struct{}{}
+				}
+			}
+		}
+	}
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ActionNames returns the JSON names of the actions set on an Action.
+func ActionNames(action *apiv1.Action) []string {
+	if action == nil {
+		return nil
+	}
+	raw, err := json.Marshal(action)
+	if err != nil {
+		return nil
+	}
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil
+	}
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	return names
+}
+
+// compareVersions compares two dotted versions, ignoring any pre-release suffix.
+func compareVersions(a, b string) int {
+	pa, pb := versionParts(a), versionParts(b)
+	for i := 0; i < 3; i++ {
+		if pa[i] != pb[i] {
+			if pa[i] < pb[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+func versionParts(v string) [3]int {
+	var parts [3]int
+	v, _, _ = strings.Cut(v, "-")
+	for i, p := range strings.SplitN(v, ".", 3) {
+		n, err := strconv.Atoi(p)
+		if err != nil {
+			break
+		}
+		parts[i] = n
+	}
+	return parts
+}
+
+// String implements fmt.Stringer.
+func (i ClusterInfo) String() string {
+	if i.Version == "" {
+		return i.Distribution
+	}
+	return fmt.Sprintf("%s %s", i.Distribution, i.Version)
+}
@@ -0,0 +1,104 @@
+package opensearch
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	apiv1 "github.com/a8uhnf/opensearch-ism-crd/api/v1"
+)
+
+var _ = Describe("ClusterInfo", func() {
+	DescribeTable("should know which actions a cluster supports",
+		func(info ClusterInfo, action string, supported bool) {
+			Expect(info.SupportsAction(action)).To(Equal(supported))
+		},
+		Entry("common action on Open Distro", ClusterInfo{Distribution: DistributionOpenDistro, Version: "7.10.2"}, "delete", true),
+		Entry("OpenSearch-only action on Open Distro", ClusterInfo{Distribution: DistributionOpenDistro, Version: "7.10.2"}, "rollup", false),
+		Entry("action newer than the cluster", ClusterInfo{Distribution: DistributionOpenSearch, Version: "2.11.0"}, "convert_index_to_remote", false),
+		Entry("action older than the cluster", ClusterInfo{Distribution: DistributionOpenSearch, Version: "2.11.0"}, "shrink", true),
+		Entry("snapshot builds", ClusterInfo{Distribution: DistributionOpenSearch, Version: "2.19.0-SNAPSHOT"}, "convert_index_to_remote", true),
+		Entry("unknown version", ClusterInfo{Distribution: DistributionOpenSearch}, "convert_index_to_remote", true),
+	)
+
+	It("should list the unsupported actions of a policy", func() {
+		policy := &apiv1.OpensearchIndexPolicy{States: []*apiv1.State{
+			{Name: "hot", Actions: []*apiv1.Action{{RollOver: &apiv1.RollOverAction{MinIndexAge: "1d"}}}},
+			{Name: "warm", Actions: []*apiv1.Action{{Shrink: &apiv1.ShrinkAction{}}, {Rollup: &apiv1.RollupAction{}}}},
+		}}
+		info := ClusterInfo{Distribution: DistributionOpenDistro, Version: "7.10.2"}
+		Expect(UnsupportedActions(info, policy)).To(Equal([]string{"rollup", "shrink"}))
+	})
+
+	DescribeTable("should detect the distribution and use its ISM prefix",
+		func(root string, distribution, version, path string) {
+			var paths []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				paths = append(paths, r.URL.Path)
+				if r.URL.Path == "/" {
+					_, _ = w.Write([]byte(root))
+					return
+				}
+				_, _ = w.Write([]byte(`{}`))
+			}))
+			defer server.Close()
+
+			ctx := context.Background()
+			client, err := NewOpenSearchClient(ctx, OpenSearchConfig{URL: server.URL})
+			Expect(err).NotTo(HaveOccurred())
+			info, err := client.ClusterInfo(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(info).To(Equal(ClusterInfo{Distribution: distribution, Version: version}))
+
+			_, err = client.GetIndexPolicy(ctx, "p")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(paths).To(Equal([]string{"/", path}))
+		},
+		Entry("OpenSearch", `{"version":{"distribution":"opensearch","number":"2.11.0"}}`,
+			DistributionOpenSearch, "2.11.0", "/_plugins/_ism/policies/p"),
+		Entry("Open Distro", `{"version":{"number":"7.10.2"}}`,
+			DistributionOpenDistro, "7.10.2", "/_opendistro/_ism/policies/p"),
+	)
+
+	It("should assume OpenSearch when the credentials may not read the root endpoint", func() {
+		calls := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			w.WriteHeader(http.StatusForbidden)
+		}))
+		defer server.Close()
+
+		ctx := context.Background()
+		client, err := NewOpenSearchClient(ctx, OpenSearchConfig{URL: server.URL})
+		Expect(err).NotTo(HaveOccurred())
+		for range 2 {
+			info, err := client.ClusterInfo(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(info).To(Equal(ClusterInfo{Distribution: DistributionOpenSearch}))
+		}
+		Expect(calls).To(Equal(1))
+	})
+
+	It("should detect the version again after a transient failure", func() {
+		status := http.StatusInternalServerError
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(status)
+			_, _ = w.Write([]byte(`{"version":{"distribution":"opensearch","number":"2.11.0"}}`))
+		}))
+		defer server.Close()
+
+		ctx := context.Background()
+		client, err := NewOpenSearchClient(ctx, OpenSearchConfig{URL: server.URL})
+		Expect(err).NotTo(HaveOccurred())
+		_, err = client.ClusterInfo(ctx)
+		Expect(err).To(HaveOccurred())
+
+		status = http.StatusOK
+		info, err := client.ClusterInfo(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(info).To(Equal(ClusterInfo{Distribution: DistributionOpenSearch, Version: "2.11.0"}))
+	})
+})
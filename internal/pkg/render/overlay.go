@@ -0,0 +1,93 @@
+package render
+
+import (
+	"sort"
+	"strings"
+
+	apiv1 "github.com/a8uhnf/opensearch-ism-crd/api/v1"
+	"github.com/a8uhnf/opensearch-ism-crd/internal/pkg/opensearch"
+)
+
+// Overlay returns a copy of base with the overlay merged into it, keyed like a
+// strategic merge patch:
+//   - description, default_state and error_notification replace those of base
+//     when set
+//   - ism_template is never inherited, since a second policy with the index
+//     patterns and priority of base conflicts with it
+//   - a state replaces the actions and transitions of the base state of the
+//     same name one by one, actions by type and transitions by target state,
+//     and is appended when base has no state of its name
+func Overlay(base, overlay *apiv1.OpensearchIndexPolicy) *apiv1.OpensearchIndexPolicy {
+	merged := base.DeepCopy()
+	overlay = overlay.DeepCopy()
+	if overlay.Description != "" {
+		merged.Description = overlay.Description
+	}
+	if overlay.DefaultState != "" {
+		merged.DefaultState = overlay.DefaultState
+	}
+	if overlay.ErrorNotification != nil {
+		merged.ErrorNotification = overlay.ErrorNotification
+	}
+	merged.ISMTemplate = overlay.ISMTemplate
+	for _, state := range overlay.States {
+		if state == nil {
+			continue
+		}
+		if target := findState(merged.States, state.Name); target != nil {
+			mergeState(target, state)
+		} else {
+			merged.States = append(merged.States, state)
+		}
+	}
+	return merged
+}
+
+func findState(states []*apiv1.State, name string) *apiv1.State {
+	for _, state := range states {
+		if state != nil && state.Name == name {
+			return state
+		}
+	}
+	return nil
+}
+
+func mergeState(target, overlay *apiv1.State) {
+	for _, action := range overlay.Actions {
+		key := actionKey(action)
+		replaced := false
+		for i, existing := range target.Actions {
+			if actionKey(existing) == key {
+				target.Actions[i] = action
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			target.Actions = append(target.Actions, action)
+		}
+	}
+	for _, transition := range overlay.Transitions {
+		if transition == nil {
+			continue
+		}
+		replaced := false
+		for i, existing := range target.Transitions {
+			if existing != nil && existing.StateName == transition.StateName {
+				target.Transitions[i] = transition
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			target.Transitions = append(target.Transitions, transition)
+		}
+	}
+}
+
+// actionKey identifies an action by its type, e.g. "rollover".
+func actionKey(action *apiv1.Action) string {
+	names := opensearch.ActionNames(action)
+	sort.Strings(names)
+	return strings.Join(names, ",")
+}
@@ -0,0 +1,81 @@
+package render
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	apiv1 "github.com/a8uhnf/opensearch-ism-crd/api/v1"
+)
+
+var _ = Describe("Overlay", func() {
+	var base *apiv1.OpensearchIndexPolicy
+
+	BeforeEach(func() {
+		base = &apiv1.OpensearchIndexPolicy{
+			Description:  "Keep logs for 30 days",
+			DefaultState: "hot",
+			States: []*apiv1.State{
+				{
+					Name: "hot",
+					Actions: []*apiv1.Action{
+						{RollOver: &apiv1.RollOverAction{MinSize: "50gb"}},
+						{ReplicaCount: &apiv1.ReplicaCountAction{NumberOfReplicas: 1}},
+					},
+					Transitions: []*apiv1.Transition{{StateName: "delete", Conditions: map[string]string{"min_index_age": "30d"}}},
+				},
+				{Name: "delete", Actions: []*apiv1.Action{{Delete: &apiv1.DeleteAction{}}}},
+			},
+			ISMTemplate: &apiv1.ISMTemplate{IndexPatterns: []string{"logs-*"}, Priority: 100},
+		}
+	})
+
+	It("keeps the base but its ism_template when the overlay is empty", func() {
+		expected := base.DeepCopy()
+		expected.ISMTemplate = nil
+		Expect(Overlay(base, &apiv1.OpensearchIndexPolicy{})).To(Equal(expected))
+	})
+
+	It("replaces actions by type and transitions by target state", func() {
+		merged := Overlay(base, &apiv1.OpensearchIndexPolicy{
+			States: []*apiv1.State{{
+				Name:        "hot",
+				Actions:     []*apiv1.Action{{RollOver: &apiv1.RollOverAction{MinSize: "10gb"}}},
+				Transitions: []*apiv1.Transition{{StateName: "delete", Conditions: map[string]string{"min_index_age": "7d"}}},
+			}},
+		})
+		Expect(merged.States).To(HaveLen(2))
+		Expect(merged.States[0].Actions).To(HaveLen(2))
+		Expect(merged.States[0].Actions[0].RollOver.MinSize).To(Equal("10gb"))
+		Expect(merged.States[0].Actions[1].ReplicaCount.NumberOfReplicas).To(Equal(base.States[0].Actions[1].ReplicaCount.NumberOfReplicas))
+		Expect(merged.States[0].Transitions).To(HaveLen(1))
+		Expect(merged.States[0].Transitions[0].Conditions).To(HaveKeyWithValue("min_index_age", "7d"))
+		Expect(base.States[0].Actions[0].RollOver.MinSize).To(Equal("50gb"))
+	})
+
+	It("appends new states, actions and transitions", func() {
+		merged := Overlay(base, &apiv1.OpensearchIndexPolicy{
+			States: []*apiv1.State{
+				{
+					Name:        "hot",
+					Actions:     []*apiv1.Action{{Close: &apiv1.CloseAction{}}},
+					Transitions: []*apiv1.Transition{{StateName: "warm", Conditions: map[string]string{"min_index_age": "7d"}}},
+				},
+				{Name: "warm", Actions: []*apiv1.Action{{ReadOnly: &apiv1.ReadOnlyAction{}}}},
+			},
+		})
+		Expect(merged.States).To(HaveLen(3))
+		Expect(merged.States[0].Actions).To(HaveLen(3))
+		Expect(merged.States[0].Transitions).To(HaveLen(2))
+		Expect(merged.States[2].Name).To(Equal("warm"))
+	})
+
+	It("replaces the ism_template", func() {
+		merged := Overlay(base, &apiv1.OpensearchIndexPolicy{
+			Description: "Keep audit logs",
+			ISMTemplate: &apiv1.ISMTemplate{IndexPatterns: []string{"audit-*"}, Priority: 200},
+		})
+		Expect(merged.Description).To(Equal("Keep audit logs"))
+		Expect(merged.ISMTemplate).To(Equal(&apiv1.ISMTemplate{IndexPatterns: []string{"audit-*"}, Priority: 200}))
+		Expect(merged.States).To(Equal(base.States))
+	})
+})
@@ -0,0 +1,72 @@
+package render
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/a8uhnf/opensearch-ism-crd/api/v1"
+)
+
+// Composed reports whether the policy of the spec is composed from a template,
+// a base or a policy source rather than written in spec.policy.
+func Composed(spec *apiv1.OSIndexPolicySpec) bool {
+	return spec.TemplateRef != nil || spec.Base != nil || spec.PolicyFrom != nil
+}
+
+// Resolve returns the policy of the object: rendered from spec.template_ref,
+// or spec.policy_from or else spec.policy, applied over the resolved policy of
+// spec.base if any. Templates and ConfigMaps are read from referenceNamespace,
+// bases are objects of the same kind and namespace.
+func Resolve(ctx context.Context, reader client.Reader, policy apiv1.IndexPolicyObject, referenceNamespace string) (*apiv1.OpensearchIndexPolicy, error) {
+	return resolve(ctx, reader, policy, referenceNamespace, nil)
+}
+
+func resolve(ctx context.Context, reader client.Reader, policy apiv1.IndexPolicyObject, referenceNamespace string, chain []string) (*apiv1.OpensearchIndexPolicy, error) {
+	if slices.Contains(chain, policy.GetName()) {
+		return nil, fmt.Errorf("base cycle %s", strings.Join(append(chain, policy.GetName()), " -> "))
+	}
+	chain = append(chain, policy.GetName())
+
+	spec := policy.GetSpec()
+	if spec.TemplateRef != nil {
+		template := &apiv1.OSIndexPolicyTemplate{}
+		if err := reader.Get(ctx, types.NamespacedName{Namespace: referenceNamespace, Name: spec.TemplateRef.Name}, template); err != nil {
+			return nil, fmt.Errorf("failed to get template %s/%s: %w", referenceNamespace, spec.TemplateRef.Name, err)
+		}
+		return Template(template, spec.TemplateRef.Parameters)
+	}
+
+	own := spec.Policy.DeepCopy()
+	if spec.PolicyFrom != nil {
+		var err error
+		if own, err = PolicyFrom(ctx, reader, referenceNamespace, spec.PolicyFrom); err != nil {
+			return nil, err
+		}
+	}
+	if spec.Base == nil {
+		return own, nil
+	}
+	base := newPolicyObject(policy)
+	key := types.NamespacedName{Namespace: policy.GetNamespace(), Name: spec.Base.Name}
+	if err := reader.Get(ctx, key, base); err != nil {
+		return nil, fmt.Errorf("failed to get base %s: %w", spec.Base.Name, err)
+	}
+	resolved, err := resolve(ctx, reader, base, referenceNamespace, chain)
+	if err != nil {
+		return nil, err
+	}
+	return Overlay(resolved, own), nil
+}
+
+// newPolicyObject returns an empty object of the kind of policy.
+func newPolicyObject(policy apiv1.IndexPolicyObject) apiv1.IndexPolicyObject {
+	if _, ok := policy.(*apiv1.ClusterOSIndexPolicy); ok {
+		return &apiv1.ClusterOSIndexPolicy{}
+	}
+	return &apiv1.OSIndexPolicy{}
+}
@@ -0,0 +1,90 @@
+package render
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/a8uhnf/opensearch-ism-crd/api/v1"
+)
+
+var _ = Describe("Resolve", func() {
+	var (
+		reader  client.Reader
+		base    *apiv1.OSIndexPolicy
+		derived *apiv1.OSIndexPolicy
+	)
+
+	BeforeEach(func() {
+		base = &apiv1.OSIndexPolicy{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "logs"},
+			Spec: apiv1.OSIndexPolicySpec{
+				PolicyID: "logs",
+				Policy: apiv1.OpensearchIndexPolicy{
+					DefaultState: "hot",
+					States: []*apiv1.State{
+						{Name: "hot", Transitions: []*apiv1.Transition{{StateName: "delete", Conditions: map[string]string{"min_index_age": "30d"}}}},
+						{Name: "delete", Actions: []*apiv1.Action{{Delete: &apiv1.DeleteAction{}}}},
+					},
+					ISMTemplate: &apiv1.ISMTemplate{IndexPatterns: []string{"logs-*"}},
+				},
+			},
+		}
+		derived = &apiv1.OSIndexPolicy{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "audit"},
+			Spec: apiv1.OSIndexPolicySpec{
+				PolicyID: "audit",
+				Base:     &apiv1.PolicyBaseRef{Name: "logs"},
+				Policy: apiv1.OpensearchIndexPolicy{
+					States: []*apiv1.State{
+						{Name: "hot", Transitions: []*apiv1.Transition{{StateName: "delete", Conditions: map[string]string{"min_index_age": "365d"}}}},
+					},
+					ISMTemplate: &apiv1.ISMTemplate{IndexPatterns: []string{"audit-*"}},
+				},
+			},
+		}
+	})
+
+	JustBeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(apiv1.AddToScheme(scheme)).To(Succeed())
+		reader = fake.NewClientBuilder().WithScheme(scheme).WithObjects(base, derived).Build()
+	})
+
+	It("returns the policy of a policy without a base or template", func() {
+		policy, err := Resolve(context.Background(), reader, base, "team-a")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(policy).To(Equal(&base.Spec.Policy))
+	})
+
+	It("applies the policy over its base", func() {
+		policy, err := Resolve(context.Background(), reader, derived, "team-a")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(policy.DefaultState).To(Equal("hot"))
+		Expect(policy.States).To(HaveLen(2))
+		Expect(policy.States[0].Transitions[0].Conditions).To(HaveKeyWithValue("min_index_age", "365d"))
+		Expect(policy.ISMTemplate.IndexPatterns).To(Equal([]string{"audit-*"}))
+	})
+
+	It("reports a missing base", func() {
+		derived.Spec.Base.Name = "metrics"
+		_, err := Resolve(context.Background(), reader, derived, "team-a")
+		Expect(err).To(MatchError(ContainSubstring("failed to get base metrics")))
+	})
+
+	Context("with a base cycle", func() {
+		BeforeEach(func() {
+			base.Spec.Base = &apiv1.PolicyBaseRef{Name: "audit"}
+		})
+
+		It("reports the cycle", func() {
+			_, err := Resolve(context.Background(), reader, derived, "team-a")
+			Expect(err).To(MatchError("base cycle audit -> logs -> audit"))
+		})
+	})
+})
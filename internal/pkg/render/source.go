@@ -0,0 +1,56 @@
+package render
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/a8uhnf/opensearch-ism-crd/api/v1"
+	"github.com/a8uhnf/opensearch-ism-crd/internal/pkg/opensearch"
+)
+
+// PolicyFrom reads the policy of the ConfigMap key the source selects, in namespace.
+func PolicyFrom(ctx context.Context, reader client.Reader, namespace string, source *apiv1.PolicySource) (*apiv1.OpensearchIndexPolicy, error) {
+	ref := source.ConfigMapKeyRef
+	configMap := &corev1.ConfigMap{}
+	if err := reader.Get(ctx, types.NamespacedName{Namespace: namespace, Name: ref.Name}, configMap); err != nil {
+		return nil, fmt.Errorf("failed to get ConfigMap %s/%s: %w", namespace, ref.Name, err)
+	}
+	document, ok := configMap.Data[ref.Key]
+	if !ok {
+		return nil, fmt.Errorf("ConfigMap %s/%s has no key %s", namespace, ref.Name, ref.Key)
+	}
+	policy, err := ParsePolicy([]byte(document))
+	if err != nil {
+		return nil, fmt.Errorf("ConfigMap %s/%s key %s: %w", namespace, ref.Name, ref.Key, err)
+	}
+	return policy, nil
+}
+
+// ParsePolicy parses native ISM JSON: the policy object, or a document wrapping
+// it in "policy" like the bodies of the ISM API. Unlike an import, fields the
+// API type cannot represent are an error rather than dropped.
+func ParsePolicy(document []byte) (*apiv1.OpensearchIndexPolicy, error) {
+	var wrapper struct {
+		Policy json.RawMessage `json:"policy"`
+	}
+	if err := json.Unmarshal(document, &wrapper); err != nil {
+		return nil, fmt.Errorf("failed to decode index policy: %w", err)
+	}
+	if wrapper.Policy != nil {
+		document = wrapper.Policy
+	}
+	policy, dropped, err := opensearch.ImportPolicy(document)
+	if err != nil {
+		return nil, err
+	}
+	if len(dropped) > 0 {
+		return nil, fmt.Errorf("unsupported policy fields %s", strings.Join(dropped, ", "))
+	}
+	return policy, nil
+}
@@ -0,0 +1,104 @@
+package render
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/a8uhnf/opensearch-ism-crd/api/v1"
+)
+
+const ismPolicy = `{
+  "description": "Keep logs for 30 days",
+  "default_state": "hot",
+  "states": [
+    {"name": "hot", "actions": [], "transitions": [{"state_name": "delete", "conditions": {"min_index_age": "30d"}}]},
+    {"name": "delete", "actions": [{"delete": {}}], "transitions": []}
+  ],
+  "ism_template": [{"index_patterns": ["logs-*"], "priority": 100}]
+}`
+
+var _ = Describe("ParsePolicy", func() {
+	It("parses a native ISM policy", func() {
+		policy, err := ParsePolicy([]byte(ismPolicy))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(policy.DefaultState).To(Equal("hot"))
+		Expect(policy.States).To(HaveLen(2))
+		Expect(policy.States[1].Actions[0].Delete).NotTo(BeNil())
+		Expect(policy.ISMTemplate).To(Equal(&apiv1.ISMTemplate{IndexPatterns: []string{"logs-*"}, Priority: 100}))
+	})
+
+	It("unwraps the policy of an ISM API document", func() {
+		policy, err := ParsePolicy([]byte(`{"_id": "logs", "_seq_no": 3, "policy": ` + ismPolicy + `}`))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(policy.Description).To(Equal("Keep logs for 30 days"))
+	})
+
+	It("rejects fields the policy cannot represent", func() {
+		_, err := ParsePolicy([]byte(`{"default_state": "hot", "states": [{"name": "hot", "unknown": true}]}`))
+		Expect(err).To(MatchError("unsupported policy fields states[0].unknown"))
+	})
+
+	It("rejects invalid JSON", func() {
+		_, err := ParsePolicy([]byte(`default_state: hot`))
+		Expect(err).To(MatchError(ContainSubstring("failed to decode index policy")))
+	})
+})
+
+var _ = Describe("PolicyFrom", func() {
+	var (
+		reader client.Reader
+		policy *apiv1.OSIndexPolicy
+	)
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(apiv1.AddToScheme(scheme)).To(Succeed())
+		Expect(corev1.AddToScheme(scheme)).To(Succeed())
+		base := &apiv1.OSIndexPolicy{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "logs"},
+			Spec: apiv1.OSIndexPolicySpec{
+				PolicyID: "logs",
+				PolicyFrom: &apiv1.PolicySource{ConfigMapKeyRef: corev1.ConfigMapKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "ism-policies"},
+					Key:                  "logs.json",
+				}},
+			},
+		}
+		policy = &apiv1.OSIndexPolicy{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "audit"},
+			Spec: apiv1.OSIndexPolicySpec{
+				PolicyID: "audit",
+				Base:     &apiv1.PolicyBaseRef{Name: "logs"},
+				Policy: apiv1.OpensearchIndexPolicy{
+					ISMTemplate: &apiv1.ISMTemplate{IndexPatterns: []string{"audit-*"}},
+				},
+			},
+		}
+		reader = fake.NewClientBuilder().WithScheme(scheme).WithObjects(base, policy, &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "ism-policies"},
+			Data:       map[string]string{"logs.json": ismPolicy},
+		}).Build()
+	})
+
+	It("resolves the bases reading their policy from a ConfigMap", func() {
+		resolved, err := Resolve(context.Background(), reader, policy, "team-a")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resolved.States).To(HaveLen(2))
+		Expect(resolved.ISMTemplate.IndexPatterns).To(Equal([]string{"audit-*"}))
+	})
+
+	It("reports missing keys", func() {
+		_, err := PolicyFrom(context.Background(), reader, "team-a", &apiv1.PolicySource{ConfigMapKeyRef: corev1.ConfigMapKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "ism-policies"},
+			Key:                  "metrics.json",
+		}})
+		Expect(err).To(MatchError("ConfigMap team-a/ism-policies has no key metrics.json"))
+	})
+})
@@ -0,0 +1,110 @@
+package render
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	apiv1 "github.com/a8uhnf/opensearch-ism-crd/api/v1"
+)
+
+// placeholder matches a parameter reference, e.g. ${retention}.
+var placeholder = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// Template renders the policy of the template with the parameters, replacing
+// ${name} in every string value of the policy. Parameters the template does
+// not declare, required parameters left unset and references to undeclared
+// parameters are errors.
+func Template(template *apiv1.OSIndexPolicyTemplate, parameters map[string]string) (*apiv1.OpensearchIndexPolicy, error) {
+	values := map[string]string{}
+	var missing []string
+	for _, parameter := range template.Spec.Parameters {
+		switch value, ok := parameters[parameter.Name]; {
+		case ok:
+			values[parameter.Name] = value
+		case parameter.Default != nil:
+			values[parameter.Name] = *parameter.Default
+		default:
+			missing = append(missing, parameter.Name)
+		}
+	}
+	var unknown []string
+	for name := range parameters {
+		if _, ok := values[name]; !ok && !slices.Contains(missing, name) {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("template %s requires parameters %s", template.Name, strings.Join(missing, ", "))
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return nil, fmt.Errorf("template %s has no parameters %s", template.Name, strings.Join(unknown, ", "))
+	}
+
+	raw, err := json.Marshal(template.Spec.Policy)
+	if err != nil {
+		return nil, err
+	}
+	var document any
+	if err := json.Unmarshal(raw, &document); err != nil {
+		return nil, err
+	}
+	undeclared := map[string]bool{}
+	document = substitute(document, values, undeclared)
+	if len(undeclared) > 0 {
+		names := make([]string, 0, len(undeclared))
+		for name := range undeclared {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("template %s references undeclared parameters %s", template.Name, strings.Join(names, ", "))
+	}
+
+	if raw, err = json.Marshal(document); err != nil {
+		return nil, err
+	}
+	rendered := &apiv1.OpensearchIndexPolicy{}
+	if err := json.Unmarshal(raw, rendered); err != nil {
+		return nil, fmt.Errorf("failed to render template %s: %w", template.Name, err)
+	}
+	return rendered, nil
+}
+
+// substitute replaces the parameter references in the strings of a decoded
+// JSON document, recording the references to unknown parameters.
+func substitute(value any, values map[string]string, undeclared map[string]bool) any {
+	switch v := value.(type) {
+	case string:
+		return placeholder.ReplaceAllStringFunc(v, func(reference string) string {
+			name := placeholder.FindStringSubmatch(reference)[1]
+			if value, ok := values[name]; ok {
+				return value
+			}
+			undeclared[name] = true
+			return reference
+		})
+	case []any:
+		for i := range v {
+			v[i] = substitute(v[i], values, undeclared)
+		}
+	case map[string]any:
+		for key := range v {
+			v[key] = substitute(v[key], values, undeclared)
+		}
+	}
+	return value
+}
+
+// Hash returns a short hash of the policy, to tell rendered policies apart in status.
+func Hash(policy *apiv1.OpensearchIndexPolicy) string {
+	// The policy only holds strings, numbers and maps of strings, which always marshal.
+	raw, _ := json.Marshal(policy)
+	sum := sha256.Sum256(raw)
+	return hex.EncodeToString(sum[:8])
+}
@@ -0,0 +1,75 @@
+package render
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/a8uhnf/opensearch-ism-crd/api/v1"
+)
+
+var _ = Describe("Template", func() {
+	var template *apiv1.OSIndexPolicyTemplate
+
+	BeforeEach(func() {
+		rolloverSize := "50gb"
+		template = &apiv1.OSIndexPolicyTemplate{
+			ObjectMeta: metav1.ObjectMeta{Name: "retention"},
+			Spec: apiv1.OSIndexPolicyTemplateSpec{
+				Parameters: []apiv1.TemplateParameter{
+					{Name: "index_pattern"},
+					{Name: "retention"},
+					{Name: "rollover_size", Default: &rolloverSize},
+				},
+				Policy: apiv1.OpensearchIndexPolicy{
+					Description:  "Keep ${index_pattern} for ${retention}",
+					DefaultState: "hot",
+					States: []*apiv1.State{
+						{
+							Name:        "hot",
+							Actions:     []*apiv1.Action{{RollOver: &apiv1.RollOverAction{MinSize: "${rollover_size}"}}},
+							Transitions: []*apiv1.Transition{{StateName: "delete", Conditions: map[string]string{"min_index_age": "${retention}"}}},
+						},
+						{Name: "delete", Actions: []*apiv1.Action{{Delete: &apiv1.DeleteAction{}}}},
+					},
+					ISMTemplate: &apiv1.ISMTemplate{IndexPatterns: []string{"${index_pattern}"}, Priority: 100},
+				},
+			},
+		}
+	})
+
+	It("replaces the parameters in the string values of the policy", func() {
+		policy, err := Template(template, map[string]string{"index_pattern": "logs-*", "retention": "30d"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(policy.Description).To(Equal("Keep logs-* for 30d"))
+		Expect(policy.States[0].Actions[0].RollOver.MinSize).To(Equal("50gb"))
+		Expect(policy.States[0].Transitions[0].Conditions).To(HaveKeyWithValue("min_index_age", "30d"))
+		Expect(policy.ISMTemplate).To(Equal(&apiv1.ISMTemplate{IndexPatterns: []string{"logs-*"}, Priority: 100}))
+		Expect(template.Spec.Policy.Description).To(Equal("Keep ${index_pattern} for ${retention}"))
+	})
+
+	It("requires the parameters without a default", func() {
+		_, err := Template(template, map[string]string{"index_pattern": "logs-*"})
+		Expect(err).To(MatchError("template retention requires parameters retention"))
+	})
+
+	It("rejects parameters the template does not declare", func() {
+		_, err := Template(template, map[string]string{"index_pattern": "logs-*", "retention": "30d", "replicas": "2"})
+		Expect(err).To(MatchError("template retention has no parameters replicas"))
+	})
+
+	It("rejects references to undeclared parameters", func() {
+		template.Spec.Policy.DefaultState = "${first_state}"
+		_, err := Template(template, map[string]string{"index_pattern": "logs-*", "retention": "30d"})
+		Expect(err).To(MatchError("template retention references undeclared parameters first_state"))
+	})
+
+	It("hashes the rendered policy", func() {
+		first, err := Template(template, map[string]string{"index_pattern": "logs-*", "retention": "30d"})
+		Expect(err).NotTo(HaveOccurred())
+		second, err := Template(template, map[string]string{"index_pattern": "logs-*", "retention": "90d"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(Hash(first)).To(HaveLen(16))
+		Expect(Hash(first)).NotTo(Equal(Hash(second)))
+	})
+})
@@ -0,0 +1,74 @@
+package targets
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/a8uhnf/opensearch-ism-crd/api/v1"
+)
+
+// Select returns the OpenSearchClusters of the namespace the targets select,
+// sorted by name, and the names listed in targets.clusters that do not exist.
+func Select(ctx context.Context, reader client.Reader, namespace string, targets *apiv1.PolicyTargets) ([]apiv1.OpenSearchCluster, []string, error) {
+	selector, err := selector(targets)
+	if err != nil {
+		return nil, nil, err
+	}
+	clusters := &apiv1.OpenSearchClusterList{}
+	if err := reader.List(ctx, clusters, client.InNamespace(namespace)); err != nil {
+		return nil, nil, fmt.Errorf("failed to list OpenSearchClusters: %w", err)
+	}
+	var selected []apiv1.OpenSearchCluster
+	found := map[string]bool{}
+	for _, cluster := range clusters.Items {
+		found[cluster.Name] = true
+		if slices.Contains(targets.Clusters, cluster.Name) || selector.Matches(labels.Set(cluster.Labels)) {
+			selected = append(selected, cluster)
+		}
+	}
+	var missing []string
+	for _, name := range targets.Clusters {
+		if !found[name] && !slices.Contains(missing, name) {
+			missing = append(missing, name)
+		}
+	}
+	slices.SortFunc(selected, func(a, b apiv1.OpenSearchCluster) int {
+		switch {
+		case a.Name < b.Name:
+			return -1
+		case a.Name > b.Name:
+			return 1
+		}
+		return 0
+	})
+	return selected, missing, nil
+}
+
+// Selects reports whether the targets select the cluster. Invalid selectors select nothing.
+func Selects(targets *apiv1.PolicyTargets, cluster *apiv1.OpenSearchCluster) bool {
+	if targets == nil {
+		return false
+	}
+	if slices.Contains(targets.Clusters, cluster.Name) {
+		return true
+	}
+	selector, err := selector(targets)
+	return err == nil && selector.Matches(labels.Set(cluster.Labels))
+}
+
+// selector converts targets.selector, which selects nothing when unset.
+func selector(targets *apiv1.PolicyTargets) (labels.Selector, error) {
+	if targets.Selector == nil {
+		return labels.Nothing(), nil
+	}
+	selector, err := metav1.LabelSelectorAsSelector(targets.Selector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid targets.selector: %w", err)
+	}
+	return selector, nil
+}
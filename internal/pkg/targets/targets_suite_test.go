@@ -0,0 +1,14 @@
+package targets
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestTargets(t *testing.T) {
+	RegisterFailHandler(Fail)
+
+	RunSpecs(t, "Targets Suite")
+}
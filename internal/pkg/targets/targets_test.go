@@ -0,0 +1,82 @@
+package targets
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/a8uhnf/opensearch-ism-crd/api/v1"
+)
+
+func cluster(namespace, name string, labels map[string]string) *apiv1.OpenSearchCluster {
+	return &apiv1.OpenSearchCluster{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name, Labels: labels},
+		Spec: apiv1.OpenSearchClusterSpec{
+			OpensearhConnection: apiv1.OpensearhConnection{URL: "https://" + name + ".example.com:9200"},
+		},
+	}
+}
+
+func names(clusters []apiv1.OpenSearchCluster) []string {
+	var names []string
+	for _, c := range clusters {
+		names = append(names, c.Name)
+	}
+	return names
+}
+
+var _ = Describe("Select", func() {
+	var reader client.Reader
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(apiv1.AddToScheme(scheme)).To(Succeed())
+		reader = fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			cluster("team-a", "eu-west", map[string]string{"tier": "logs"}),
+			cluster("team-a", "us-east", map[string]string{"tier": "logs"}),
+			cluster("team-a", "ap-south", map[string]string{"tier": "metrics"}),
+			cluster("team-b", "eu-central", map[string]string{"tier": "logs"}),
+		).Build()
+	})
+
+	It("selects clusters by name and reports missing ones", func() {
+		selected, missing, err := Select(context.Background(), reader, "team-a", &apiv1.PolicyTargets{
+			Clusters: []string{"us-east", "eu-central"},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(names(selected)).To(Equal([]string{"us-east"}))
+		Expect(missing).To(Equal([]string{"eu-central"}))
+	})
+
+	It("selects clusters by label, once when also named", func() {
+		selected, missing, err := Select(context.Background(), reader, "team-a", &apiv1.PolicyTargets{
+			Clusters: []string{"eu-west"},
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"tier": "logs"}},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(names(selected)).To(Equal([]string{"eu-west", "us-east"}))
+		Expect(missing).To(BeEmpty())
+	})
+
+	It("rejects invalid selectors", func() {
+		_, _, err := Select(context.Background(), reader, "team-a", &apiv1.PolicyTargets{
+			Selector: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "tier", Operator: "Near"}}},
+		})
+		Expect(err).To(MatchError(ContainSubstring("invalid targets.selector")))
+	})
+})
+
+var _ = Describe("Selects", func() {
+	It("matches names and labels", func() {
+		eu := cluster("team-a", "eu-west", map[string]string{"tier": "logs"})
+		Expect(Selects(&apiv1.PolicyTargets{Clusters: []string{"eu-west"}}, eu)).To(BeTrue())
+		Expect(Selects(&apiv1.PolicyTargets{Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"tier": "logs"}}}, eu)).To(BeTrue())
+		Expect(Selects(&apiv1.PolicyTargets{Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"tier": "metrics"}}}, eu)).To(BeFalse())
+		Expect(Selects(nil, eu)).To(BeFalse())
+	})
+})
@@ -0,0 +1,98 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	batchv1 "github.com/a8uhnf/opensearch-ism-crd/api/v1"
+)
+
+// log is for logging in this package.
+var clusterosindexpolicylog = logf.Log.WithName("clusterosindexpolicy-resource")
+
+// SetupClusterOSIndexPolicyWebhookWithManager registers the webhook for ClusterOSIndexPolicy in the manager.
+// The templates, ConfigMaps and OpenSearchClusters the policies reference are read from clusterNamespace.
+func SetupClusterOSIndexPolicyWebhookWithManager(mgr ctrl.Manager, clusterNamespace string) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&batchv1.ClusterOSIndexPolicy{}).
+		WithValidator(&ClusterOSIndexPolicyCustomValidator{
+			Client:           mgr.GetClient(),
+			ClusterNamespace: clusterNamespace,
+		}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/validate-batch-a8uhnf-com-v1-clusterosindexpolicy,mutating=false,failurePolicy=fail,sideEffects=None,groups=batch.a8uhnf.com,resources=clusterosindexpolicies,verbs=create;update,versions=v1,name=vclusterosindexpolicy-v1.kb.io,admissionReviewVersions=v1
+
+// ClusterOSIndexPolicyCustomValidator validates ClusterOSIndexPolicies like the
+// OSIndexPolicy validator does, without the namespace guardrails, which do not
+// apply to cluster-scoped policies.
+type ClusterOSIndexPolicyCustomValidator struct {
+	// Client reads the templates, ConfigMaps and OpenSearchClusters the policy references.
+	// Without it, the policy is validated as written.
+	Client client.Reader
+	// ClusterNamespace is the namespace the references of cluster-scoped policies are read from
+	ClusterNamespace string
+}
+
+var _ webhook.CustomValidator = &ClusterOSIndexPolicyCustomValidator{}
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type ClusterOSIndexPolicy.
+func (v *ClusterOSIndexPolicyCustomValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	policy, ok := obj.(*batchv1.ClusterOSIndexPolicy)
+	if !ok {
+		return nil, fmt.Errorf("expected a ClusterOSIndexPolicy object but got %T", obj)
+	}
+	clusterosindexpolicylog.Info("Validation for ClusterOSIndexPolicy upon creation", "name", policy.GetName())
+
+	return v.validator().validatePolicy(ctx, policy, nil, v.ClusterNamespace)
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type ClusterOSIndexPolicy.
+func (v *ClusterOSIndexPolicyCustomValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	policy, ok := newObj.(*batchv1.ClusterOSIndexPolicy)
+	if !ok {
+		return nil, fmt.Errorf("expected a ClusterOSIndexPolicy object for the newObj but got %T", newObj)
+	}
+	clusterosindexpolicylog.Info("Validation for ClusterOSIndexPolicy upon update", "name", policy.GetName())
+
+	var old batchv1.IndexPolicyObject
+	if oldPolicy, ok := oldObj.(*batchv1.ClusterOSIndexPolicy); ok {
+		old = oldPolicy
+	}
+	return v.validator().validatePolicy(ctx, policy, old, v.ClusterNamespace)
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type ClusterOSIndexPolicy.
+// Deletion is not validated.
+func (v *ClusterOSIndexPolicyCustomValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validator returns the OSIndexPolicy validator the checks are shared with.
+// Cluster-scoped policies are never prefixed with a namespace.
+func (v *ClusterOSIndexPolicyCustomValidator) validator() *OSIndexPolicyCustomValidator {
+	return &OSIndexPolicyCustomValidator{Client: v.Client}
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	batchv1 "github.com/a8uhnf/opensearch-ism-crd/api/v1"
+)
+
+var _ = Describe("ClusterOSIndexPolicy Webhook", func() {
+	var (
+		obj       *batchv1.ClusterOSIndexPolicy
+		oldObj    *batchv1.ClusterOSIndexPolicy
+		validator ClusterOSIndexPolicyCustomValidator
+	)
+
+	BeforeEach(func() {
+		obj = &batchv1.ClusterOSIndexPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "logs"},
+			Spec: batchv1.OSIndexPolicySpec{
+				PolicyID:            "logs",
+				OpensearhConnection: batchv1.OpensearhConnection{URL: "https://opensearch:9200"},
+			},
+		}
+		oldObj = &batchv1.ClusterOSIndexPolicy{}
+		validator = ClusterOSIndexPolicyCustomValidator{ClusterNamespace: "opensearch-system"}
+	})
+
+	It("Should admit a valid policy", func() {
+		Expect(validator.ValidateCreate(ctx, obj)).Error().NotTo(HaveOccurred())
+		Expect(validator.ValidateUpdate(ctx, oldObj, obj)).Error().NotTo(HaveOccurred())
+	})
+
+	It("Should deny a policy without policy_id or connection", func() {
+		obj.Spec.PolicyID = ""
+		Expect(validator.ValidateCreate(ctx, obj)).Error().To(MatchError(ContainSubstring("policy_id must be specified in the ClusterOSIndexPolicy spec")))
+
+		obj.Spec.PolicyID = "logs"
+		obj.Spec.OpensearhConnection.URL = ""
+		Expect(validator.ValidateUpdate(ctx, oldObj, obj)).Error().To(MatchError(ContainSubstring("opensearch_connection.url or targets")))
+	})
+
+	It("Should deny an empty auth block", func() {
+		obj.Spec.OpensearhConnection.Auth = &batchv1.OpensearchAuth{}
+		Expect(validator.ValidateCreate(ctx, obj)).Error().To(HaveOccurred())
+	})
+
+	It("Should deny actions the cluster version does not support", func() {
+		oldObj.Status.ClusterDistribution = "opendistro"
+		oldObj.Status.ClusterVersion = "1.13.2"
+		obj.Spec.Policy.DefaultState = "warm"
+		obj.Spec.Policy.States = []*batchv1.State{{Name: "warm", Actions: []*batchv1.Action{{Shrink: &batchv1.ShrinkAction{}}}}}
+		Expect(validator.ValidateUpdate(ctx, oldObj, obj)).Error().To(MatchError(ContainSubstring("shrink")))
+	})
+
+	It("Should validate the policy merged over its base", func() {
+		scheme := runtime.NewScheme()
+		Expect(batchv1.AddToScheme(scheme)).To(Succeed())
+		validator.Client = fake.NewClientBuilder().WithScheme(scheme).Build()
+		obj.Spec.Base = &batchv1.PolicyBaseRef{Name: "retention"}
+		Expect(validator.ValidateCreate(ctx, obj)).Error().To(MatchError(ContainSubstring("failed to resolve policy")))
+	})
+})
@@ -19,12 +19,16 @@ package v1
 import (
 	"context"
 	"fmt"
+	"strings"
 
-	"crypto/tls"
+	"github.com/a8uhnf/opensearch-ism-crd/internal/pkg/guardrails"
 	"github.com/a8uhnf/opensearch-ism-crd/internal/pkg/opensearch"
+	"github.com/a8uhnf/opensearch-ism-crd/internal/pkg/render"
+	"github.com/a8uhnf/opensearch-ism-crd/internal/pkg/targets"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/runtime"
-	"net/http"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
@@ -37,9 +41,13 @@ import (
 var osindexpolicylog = logf.Log.WithName("osindexpolicy-resource")
 
 // SetupOSIndexPolicyWebhookWithManager registers the webhook for OSIndexPolicy in the manager.
-func SetupOSIndexPolicyWebhookWithManager(mgr ctrl.Manager) error {
+// With namespacePrefix, policy IDs and index patterns are prefixed with the namespace like the controller does.
+func SetupOSIndexPolicyWebhookWithManager(mgr ctrl.Manager, namespacePrefix bool) error {
 	return ctrl.NewWebhookManagedBy(mgr).For(&batchv1.OSIndexPolicy{}).
-		WithValidator(&OSIndexPolicyCustomValidator{}).
+		WithValidator(&OSIndexPolicyCustomValidator{
+			Client:          mgr.GetClient(),
+			NamespacePrefix: namespacePrefix,
+		}).
 		WithDefaulter(&OSIndexPolicyCustomDefaulter{}).
 		Complete()
 }
@@ -85,31 +93,27 @@ func (d *OSIndexPolicyCustomDefaulter) Default(_ context.Context, obj runtime.Ob
 // NOTE: The +kubebuilder:object:generate=false marker prevents controller-gen from generating DeepCopy methods,
 // as this struct is used only for temporary operations and does not need to be deeply copied.
 type OSIndexPolicyCustomValidator struct {
-	// TODO(user): Add more fields as needed for validation
+	// Client reads the OSIndexPolicyGuardrails. Without it, guardrails are not enforced.
+	Client client.Reader
+	// NamespacePrefix validates the namespace-prefixed policy IDs and index patterns
+	NamespacePrefix bool
 }
 
 var _ webhook.CustomValidator = &OSIndexPolicyCustomValidator{}
 
 // ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type OSIndexPolicy.
-func (v *OSIndexPolicyCustomValidator) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+func (v *OSIndexPolicyCustomValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
 	osindexpolicy, ok := obj.(*batchv1.OSIndexPolicy)
 	if !ok {
 		return nil, fmt.Errorf("expected a OSIndexPolicy object but got %T", obj)
 	}
 	osindexpolicylog.Info("Validation for OSIndexPolicy upon creation", "name", osindexpolicy.GetName())
 
-	if osindexpolicy.Spec.PolicyID == "" {
-		return nil, fmt.Errorf("policy_id must be specified in the OSIndexPolicy spec")
-	}
-	if osindexpolicy.Spec.OpensearhConnection.URL == "" {
-		return nil, fmt.Errorf("opensearch_connection.url must be specified in the OSIndexPolicy spec")
-	}
-
-	return nil, nil
+	return v.validatePolicy(ctx, osindexpolicy, nil, osindexpolicy.Namespace)
 }
 
 // ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type OSIndexPolicy.
-func (v *OSIndexPolicyCustomValidator) ValidateUpdate(_ context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+func (v *OSIndexPolicyCustomValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
 	osindexpolicy, ok := newObj.(*batchv1.OSIndexPolicy)
 	if !ok {
 		return nil, fmt.Errorf("expected a OSIndexPolicy object for the newObj but got %T", newObj)
@@ -118,17 +122,234 @@ func (v *OSIndexPolicyCustomValidator) ValidateUpdate(_ context.Context, oldObj,
 
 	// TODO(user): fill in your validation logic upon object update.
 
-	if osindexpolicy.Spec.PolicyID == "" {
-		return nil, fmt.Errorf("policy_id must be specified in the OSIndexPolicy spec")
+	// The status of the stored object holds the version detected by the controller.
+	var old batchv1.IndexPolicyObject
+	if oldPolicy, ok := oldObj.(*batchv1.OSIndexPolicy); ok {
+		old = oldPolicy
+	}
+	return v.validatePolicy(ctx, osindexpolicy, old, osindexpolicy.Namespace)
+}
+
+// validatePolicy validates the spec of an OSIndexPolicy or a ClusterOSIndexPolicy,
+// resolved from its template or base with the references read from
+// referenceNamespace. The default_state and transitions must name states of the
+// resolved policy. On update, the actions are checked against the cluster
+// versions recorded in the status of old.
+func (v *OSIndexPolicyCustomValidator) validatePolicy(ctx context.Context, policy, old batchv1.IndexPolicyObject, referenceNamespace string) (admission.Warnings, error) {
+	kind := "OSIndexPolicy"
+	if policy.GetNamespace() == "" {
+		kind = "ClusterOSIndexPolicy"
+	}
+	if policy.GetSpec().PolicyID == "" {
+		return nil, fmt.Errorf("policy_id must be specified in the %s spec", kind)
+	}
+	if policy.GetSpec().Targets == nil && policy.GetSpec().OpensearhConnection.URL == "" {
+		return nil, fmt.Errorf("opensearch_connection.url or targets must be specified in the %s spec", kind)
 	}
-	if osindexpolicy.Spec.OpensearhConnection.URL == "" {
-		return nil, fmt.Errorf("opensearch_connection.url must be specified in the OSIndexPolicy spec")
+	if policy.GetSpec().Targets != nil && !equality.Semantic.DeepEqual(policy.GetSpec().OpensearhConnection, batchv1.OpensearhConnection{}) {
+		return nil, fmt.Errorf("targets and opensearch_connection are mutually exclusive in the %s spec", kind)
+	}
+	if err := validateAuth(policy.GetSpec().OpensearhConnection); err != nil {
+		return nil, err
+	}
+	spec, err := v.resolvedSpec(ctx, policy, referenceNamespace)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateStates(&spec.Policy); err != nil {
+		return nil, err
+	}
+	warnings, err := v.validateGuardrails(ctx, policy.GetNamespace(), referenceNamespace, spec)
+	if err != nil {
+		return warnings, err
+	}
+	if old != nil {
+		if err := validateActions(&spec.Policy, *old.GetStatus()); err != nil {
+			return warnings, err
+		}
 	}
 
-	return nil, nil
+	return warnings, nil
+}
+
+// validateAuth requires exactly one authentication mode in opensearch_connection.auth.
+// The legacy username, password and credentials_secret_ref fields are basic auth
+// and cannot be combined with it.
+func validateAuth(conn batchv1.OpensearhConnection) error {
+	auth := conn.Auth
+	if auth == nil {
+		return nil
+	}
+	if conn.Username != "" || conn.Password != "" || conn.CredentialsSecretRef != nil {
+		return fmt.Errorf("opensearch_connection.auth cannot be combined with username, password or credentials_secret_ref")
+	}
+	var modes []string
+	if auth.Basic != nil {
+		modes = append(modes, "basic")
+	}
+	if auth.BearerTokenSecretRef != nil {
+		modes = append(modes, "bearer_token_secret_ref")
+	}
+	if auth.APIKeySecretRef != nil {
+		modes = append(modes, "api_key_secret_ref")
+	}
+	if auth.ClientCertificate != nil {
+		modes = append(modes, "client_certificate")
+	}
+	if auth.AWS != nil {
+		modes = append(modes, "aws")
+	}
+	if len(modes) != 1 {
+		return fmt.Errorf("opensearch_connection.auth must set exactly one of basic, bearer_token_secret_ref, api_key_secret_ref, client_certificate or aws, got %d", len(modes))
+	}
+	return nil
+}
+
+// validateGuardrails rejects clusters and index patterns that the
+// OSIndexPolicyGuardrails of the namespace do not allow, checking the connection
+// of every OpenSearchCluster selected by spec.targets, and with NamespacePrefix
+// IDs and patterns reaching into the prefix of another namespace. Target
+// clusters that do not exist yet are returned as warnings. ClusterOSIndexPolicies,
+// with an empty namespace, are not restricted.
+func (v *OSIndexPolicyCustomValidator) validateGuardrails(ctx context.Context, namespace, referenceNamespace string, spec *batchv1.OSIndexPolicySpec) (admission.Warnings, error) {
+	if v.Client == nil {
+		return nil, nil
+	}
+	if v.NamespacePrefix && namespace != "" {
+		violations, err := guardrails.CheckPrefix(ctx, v.Client, namespace, spec)
+		if err != nil {
+			return nil, err
+		}
+		if err := guardrailsError(namespace, violations); err != nil {
+			return nil, err
+		}
+	}
+	if spec.Targets == nil {
+		if namespace == "" {
+			return nil, nil
+		}
+		violations, err := guardrails.Check(ctx, v.Client, namespace, spec)
+		if err != nil {
+			return nil, err
+		}
+		return nil, guardrailsError(namespace, violations)
+	}
+	if referenceNamespace == "" {
+		return nil, nil
+	}
+
+	clusters, warnings, err := v.selectTargets(ctx, referenceNamespace, spec.Targets)
+	if err != nil || namespace == "" {
+		return warnings, err
+	}
+	var violations []string
+	for _, cluster := range clusters {
+		target := spec.DeepCopy()
+		target.OpensearhConnection = cluster.Spec.OpensearhConnection
+		target.Targets = nil
+		clusterViolations, err := guardrails.Check(ctx, v.Client, namespace, target)
+		if err != nil {
+			return warnings, err
+		}
+		for _, violation := range clusterViolations {
+			violations = append(violations, fmt.Sprintf("OpenSearchCluster %s: %s", cluster.Name, violation))
+		}
+	}
+	return warnings, guardrailsError(namespace, violations)
+}
+
+func guardrailsError(namespace string, violations []string) error {
+	if len(violations) == 0 {
+		return nil
+	}
+	return fmt.Errorf("policy breaks the guardrails of namespace %s: %s", namespace, strings.Join(violations, "; "))
+}
+
+// selectTargets returns the OpenSearchClusters the targets select, with a
+// warning for each named cluster that does not exist.
+func (v *OSIndexPolicyCustomValidator) selectTargets(ctx context.Context, namespace string, policyTargets *batchv1.PolicyTargets) ([]batchv1.OpenSearchCluster, admission.Warnings, error) {
+	clusters, missing, err := targets.Select(ctx, v.Client, namespace, policyTargets)
+	if err != nil {
+		return nil, nil, err
+	}
+	var warnings admission.Warnings
+	for _, name := range missing {
+		warnings = append(warnings, fmt.Sprintf("OpenSearchCluster %s not found in namespace %s", name, namespace))
+	}
+	return clusters, warnings, nil
+}
+
+// resolvedSpec returns the spec the controller sends to OpenSearch, with the
+// policy resolved from its template or base, so the merged result is what gets
+// validated. Without a Client, the policy is validated as written.
+func (v *OSIndexPolicyCustomValidator) resolvedSpec(ctx context.Context, policy batchv1.IndexPolicyObject, referenceNamespace string) (*batchv1.OSIndexPolicySpec, error) {
+	spec := policy.GetSpec().DeepCopy()
+	if v.Client != nil && render.Composed(spec) {
+		resolved, err := render.Resolve(ctx, v.Client, policy, referenceNamespace)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve policy: %w", err)
+		}
+		spec.Policy = *resolved
+	}
+	if v.NamespacePrefix {
+		guardrails.WithNamespacePrefix(spec, policy.GetNamespace())
+	}
+	return spec, nil
+}
+
+// validateStates rejects policies whose default_state or transitions name a
+// state the policy does not have. A policy without states or default_state is
+// not checked.
+func validateStates(policy *batchv1.OpensearchIndexPolicy) error {
+	if policy.DefaultState == "" && len(policy.States) == 0 {
+		return nil
+	}
+	names := map[string]bool{}
+	for _, state := range policy.States {
+		if state != nil {
+			names[state.Name] = true
+		}
+	}
+	if !names[policy.DefaultState] {
+		return fmt.Errorf("default_state %q is not a state of the policy", policy.DefaultState)
+	}
+	for _, state := range policy.States {
+		if state == nil {
+			continue
+		}
+		for _, transition := range state.Transitions {
+			if transition != nil && !names[transition.StateName] {
+				return fmt.Errorf("state %s transitions to %q, which is not a state of the policy", state.Name, transition.StateName)
+			}
+		}
+	}
+	return nil
+}
+
+// validateActions rejects actions that the cluster versions recorded in status,
+// of the cluster or of every target, do not support.
+// Nothing is rejected until the controller has detected a version.
+func validateActions(policy *batchv1.OpensearchIndexPolicy, status batchv1.OSIndexPolicyStatus) error {
+	var infos []opensearch.ClusterInfo
+	if status.ClusterDistribution != "" {
+		infos = append(infos, opensearch.ClusterInfo{Distribution: status.ClusterDistribution, Version: status.ClusterVersion})
+	}
+	for _, target := range status.Targets {
+		if target.ClusterDistribution != "" {
+			infos = append(infos, opensearch.ClusterInfo{Distribution: target.ClusterDistribution, Version: target.ClusterVersion})
+		}
+	}
+	for _, info := range infos {
+		if unsupported := opensearch.UnsupportedActions(info, policy); len(unsupported) > 0 {
+			return fmt.Errorf("policy uses actions not supported by %s: %s", info, strings.Join(unsupported, ", "))
+		}
+	}
+	return nil
 }
 
 // ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type OSIndexPolicy.
+// Deletion is not validated: the controller deletes the ISM policy from OpenSearch
+// before it removes its finalizer.
 func (v *OSIndexPolicyCustomValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
 	osindexpolicy, ok := obj.(*batchv1.OSIndexPolicy)
 	if !ok {
@@ -136,22 +357,5 @@ func (v *OSIndexPolicyCustomValidator) ValidateDelete(ctx context.Context, obj r
 	}
 	osindexpolicylog.Info("Validation for OSIndexPolicy upon deletion", "name", osindexpolicy.GetName())
 
-	opensearchClient, err := opensearch.NewOpenSearchClient(ctx, opensearch.OpenSearchConfig{
-		URL:      osindexpolicy.Spec.OpensearhConnection.URL,      // Use the URL from the request spec
-		TLSConfig: &http.Transport{
-			TLSClientConfig: &tls.Config{
-				InsecureSkipVerify: true, // Set to true for testing purposes, should be false in production
-			},
-		},
-	})
-
-	err = opensearchClient.DeleteIndexPolicy(ctx, osindexpolicy.Spec.PolicyID)
-	if err != nil {
-		osindexpolicylog.Error(err, "Failed to delete index policy in OpenSearch", "policyName", osindexpolicy.Spec.PolicyID)
-		// If the index policy cannot be deleted, return an error to requeue the request.
-		return nil, fmt.Errorf("failed to delete index policy in OpenSearch: %w", err)
-	}
-	osindexpolicylog.Info("Index policy deleted successfully in OpenSearch", "policyName", osindexpolicy.Spec.PolicyID)
-
 	return nil, nil
 }
@@ -19,6 +19,10 @@ package v1
 import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	batchv1 "github.com/a8uhnf/opensearch-ism-crd/api/v1"
 	// TODO (user): Add any additional imports if needed
@@ -82,6 +86,277 @@ var _ = Describe("OSIndexPolicy Webhook", func() {
 		//     obj.SomeRequiredField = "updated_value"
 		//     Expect(validator.ValidateUpdate(ctx, oldObj, obj)).To(BeNil())
 		// })
+
+		BeforeEach(func() {
+			obj.Spec.PolicyID = "logs"
+			obj.Spec.OpensearhConnection.URL = "https://opensearch:9200"
+		})
+
+		It("Should admit a connection without auth", func() {
+			Expect(validator.ValidateCreate(ctx, obj)).Error().NotTo(HaveOccurred())
+		})
+
+		It("Should admit exactly one authentication mode", func() {
+			obj.Spec.OpensearhConnection.Auth = &batchv1.OpensearchAuth{
+				BearerTokenSecretRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "opensearch-jwt"},
+					Key:                  "token",
+				},
+			}
+			Expect(validator.ValidateCreate(ctx, obj)).Error().NotTo(HaveOccurred())
+			Expect(validator.ValidateUpdate(ctx, oldObj, obj)).Error().NotTo(HaveOccurred())
+		})
+
+		It("Should deny several authentication modes", func() {
+			obj.Spec.OpensearhConnection.Auth = &batchv1.OpensearchAuth{
+				APIKeySecretRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "opensearch-api-key"},
+					Key:                  "key",
+				},
+				ClientCertificate: &batchv1.ClientCertificateAuth{
+					SecretRef: corev1.LocalObjectReference{Name: "opensearch-client-tls"},
+				},
+			}
+			Expect(validator.ValidateCreate(ctx, obj)).Error().To(HaveOccurred())
+			Expect(validator.ValidateUpdate(ctx, oldObj, obj)).Error().To(HaveOccurred())
+		})
+
+		It("Should deny an empty auth block", func() {
+			obj.Spec.OpensearhConnection.Auth = &batchv1.OpensearchAuth{}
+			Expect(validator.ValidateCreate(ctx, obj)).Error().To(HaveOccurred())
+		})
+
+		It("Should deny auth combined with the legacy basic auth fields", func() {
+			obj.Spec.OpensearhConnection.Username = "admin"
+			obj.Spec.OpensearhConnection.Auth = &batchv1.OpensearchAuth{
+				AWS: &batchv1.AWSAuth{Region: "eu-west-1"},
+			}
+			Expect(validator.ValidateCreate(ctx, obj)).Error().To(HaveOccurred())
+		})
+
+		It("Should deny clusters the guardrails of the namespace do not allow", func() {
+			scheme := runtime.NewScheme()
+			Expect(batchv1.AddToScheme(scheme)).To(Succeed())
+			validator.Client = fake.NewClientBuilder().WithScheme(scheme).WithObjects(&batchv1.OSIndexPolicyGuardrail{
+				ObjectMeta: metav1.ObjectMeta{Name: "team-a"},
+				Spec: batchv1.OSIndexPolicyGuardrailSpec{
+					Namespaces:      []string{"team-a"},
+					AllowedClusters: []string{"https://logs.example.com:9200"},
+				},
+			}).Build()
+			obj.Namespace = "team-a"
+			Expect(validator.ValidateCreate(ctx, obj)).Error().To(MatchError(ContainSubstring("not allowed in namespace team-a")))
+
+			obj.Spec.OpensearhConnection.URL = "https://logs.example.com:9200"
+			Expect(validator.ValidateUpdate(ctx, oldObj, obj)).Error().NotTo(HaveOccurred())
+		})
+
+		It("Should validate the policy merged over its base", func() {
+			scheme := runtime.NewScheme()
+			Expect(batchv1.AddToScheme(scheme)).To(Succeed())
+			validator.Client = fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+				&batchv1.OSIndexPolicy{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "logs"},
+					Spec: batchv1.OSIndexPolicySpec{
+						PolicyID: "logs",
+						Policy: batchv1.OpensearchIndexPolicy{
+							ISMTemplate: &batchv1.ISMTemplate{IndexPatterns: []string{"*"}},
+						},
+					},
+				},
+				&batchv1.OSIndexPolicyGuardrail{
+					ObjectMeta: metav1.ObjectMeta{Name: "team-a"},
+					Spec: batchv1.OSIndexPolicyGuardrailSpec{
+						Namespaces:                  []string{"team-a"},
+						AllowedIndexPatternPrefixes: []string{"team-a-"},
+					},
+				},
+			).Build()
+			obj.Namespace = "team-a"
+			obj.Spec.Base = &batchv1.PolicyBaseRef{Name: "logs"}
+			By("not inheriting the ism_template of the base")
+			Expect(validator.ValidateCreate(ctx, obj)).Error().NotTo(HaveOccurred())
+
+			obj.Spec.Policy.ISMTemplate = &batchv1.ISMTemplate{IndexPatterns: []string{"*"}}
+			Expect(validator.ValidateCreate(ctx, obj)).Error().To(MatchError(ContainSubstring("guardrails of namespace team-a")))
+
+			obj.Spec.Policy.ISMTemplate = &batchv1.ISMTemplate{IndexPatterns: []string{"team-a-logs-*"}}
+			Expect(validator.ValidateCreate(ctx, obj)).Error().NotTo(HaveOccurred())
+
+			obj.Spec.Base.Name = "metrics"
+			Expect(validator.ValidateUpdate(ctx, oldObj, obj)).Error().To(MatchError(ContainSubstring("failed to resolve policy")))
+		})
+
+		It("Should deny states the merged policy does not have", func() {
+			obj.Spec.Policy.DefaultState = "warm"
+			obj.Spec.Policy.States = []*batchv1.State{{Name: "hot"}}
+			Expect(validator.ValidateCreate(ctx, obj)).Error().To(MatchError(ContainSubstring(`default_state "warm" is not a state of the policy`)))
+
+			scheme := runtime.NewScheme()
+			Expect(batchv1.AddToScheme(scheme)).To(Succeed())
+			validator.Client = fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+				&batchv1.OSIndexPolicy{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "logs"},
+					Spec: batchv1.OSIndexPolicySpec{
+						PolicyID: "logs",
+						Policy: batchv1.OpensearchIndexPolicy{
+							DefaultState: "hot",
+							States:       []*batchv1.State{{Name: "hot"}, {Name: "delete"}},
+						},
+					},
+				},
+			).Build()
+			obj.Namespace = "team-a"
+			obj.Spec.Base = &batchv1.PolicyBaseRef{Name: "logs"}
+			obj.Spec.Policy = batchv1.OpensearchIndexPolicy{
+				States: []*batchv1.State{{Name: "hot", Transitions: []*batchv1.Transition{{StateName: "warm"}}}},
+			}
+			Expect(validator.ValidateCreate(ctx, obj)).Error().To(MatchError(ContainSubstring(`state hot transitions to "warm"`)))
+
+			obj.Spec.Policy.States[0].Transitions[0].StateName = "delete"
+			Expect(validator.ValidateCreate(ctx, obj)).Error().NotTo(HaveOccurred())
+		})
+
+		It("Should deny policy IDs reaching into the prefix of another namespace", func() {
+			scheme := runtime.NewScheme()
+			Expect(batchv1.AddToScheme(scheme)).To(Succeed())
+			Expect(corev1.AddToScheme(scheme)).To(Succeed())
+			validator.Client = fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+				&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team"}},
+				&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-a"}},
+			).Build()
+			validator.NamespacePrefix = true
+			obj.Namespace = "team"
+			obj.Spec.PolicyID = "a-logs"
+			Expect(validator.ValidateCreate(ctx, obj)).Error().To(MatchError(ContainSubstring("falls into the prefix of namespace team-a")))
+
+			obj.Spec.PolicyID = "logs"
+			Expect(validator.ValidateCreate(ctx, obj)).Error().NotTo(HaveOccurred())
+		})
+
+		It("Should check the guardrails against every target cluster", func() {
+			scheme := runtime.NewScheme()
+			Expect(batchv1.AddToScheme(scheme)).To(Succeed())
+			validator.Client = fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+				&batchv1.OpenSearchCluster{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "eu"},
+					Spec: batchv1.OpenSearchClusterSpec{
+						OpensearhConnection: batchv1.OpensearhConnection{URL: "https://logs.example.com:9200"},
+					},
+				},
+				&batchv1.OpenSearchCluster{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "us"},
+					Spec: batchv1.OpenSearchClusterSpec{
+						OpensearhConnection: batchv1.OpensearhConnection{URL: "https://us.example.com:9200"},
+					},
+				},
+				&batchv1.OSIndexPolicyGuardrail{
+					ObjectMeta: metav1.ObjectMeta{Name: "team-a"},
+					Spec: batchv1.OSIndexPolicyGuardrailSpec{
+						Namespaces:      []string{"team-a"},
+						AllowedClusters: []string{"https://logs.example.com:9200"},
+					},
+				},
+			).Build()
+			obj.Namespace = "team-a"
+			obj.Spec.OpensearhConnection = batchv1.OpensearhConnection{}
+			obj.Spec.Targets = &batchv1.PolicyTargets{Clusters: []string{"eu", "us"}}
+			Expect(validator.ValidateCreate(ctx, obj)).Error().To(MatchError(ContainSubstring("OpenSearchCluster us: ")))
+
+			obj.Spec.Targets.Clusters = []string{"eu", "ap"}
+			warnings, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(ConsistOf(ContainSubstring("ap")))
+		})
+
+		It("Should deny targets with connection settings", func() {
+			obj.Spec.OpensearhConnection = batchv1.OpensearhConnection{
+				Auth: &batchv1.OpensearchAuth{BearerTokenSecretRef: &corev1.SecretKeySelector{Key: "token"}},
+			}
+			obj.Spec.Targets = &batchv1.PolicyTargets{Clusters: []string{"eu"}}
+			Expect(validator.ValidateCreate(ctx, obj)).Error().To(MatchError(ContainSubstring("targets and opensearch_connection are mutually exclusive")))
+
+			obj.Spec.OpensearhConnection = batchv1.OpensearhConnection{TLS: &batchv1.OpensearchTLS{InsecureSkipVerify: true}}
+			Expect(validator.ValidateUpdate(ctx, oldObj, obj)).Error().To(MatchError(ContainSubstring("targets and opensearch_connection are mutually exclusive")))
+
+			obj.Spec.OpensearhConnection = batchv1.OpensearhConnection{}
+			Expect(validator.ValidateCreate(ctx, obj)).Error().NotTo(HaveOccurred())
+		})
 	})
 
+	Context("When creating OSIndexPolicy through the API server", func() {
+		It("Should admit a policy rendered from a template", func() {
+			template := &batchv1.OSIndexPolicyTemplate{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "retention"},
+				Spec: batchv1.OSIndexPolicyTemplateSpec{
+					Policy: batchv1.OpensearchIndexPolicy{DefaultState: "hot", States: []*batchv1.State{{Name: "hot"}}},
+				},
+			}
+			Expect(k8sClient.Create(ctx, template)).To(Succeed())
+
+			By("sending the policy through the defaulting webhook and the CRD validation")
+			policy := &batchv1.OSIndexPolicy{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "from-template"},
+				Spec: batchv1.OSIndexPolicySpec{
+					PolicyID:            "from-template",
+					OpensearhConnection: batchv1.OpensearhConnection{URL: "https://opensearch:9200"},
+					TemplateRef:         &batchv1.PolicyTemplateRef{Name: "retention"},
+				},
+			}
+			Expect(k8sClient.Create(ctx, policy)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, policy)).To(Succeed())
+
+			By("denying a policy setting both")
+			policy = &batchv1.OSIndexPolicy{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "both"},
+				Spec: batchv1.OSIndexPolicySpec{
+					PolicyID:            "both",
+					OpensearhConnection: batchv1.OpensearhConnection{URL: "https://opensearch:9200"},
+					TemplateRef:         &batchv1.PolicyTemplateRef{Name: "retention"},
+					Policy:              batchv1.OpensearchIndexPolicy{DefaultState: "hot"},
+				},
+			}
+			Expect(k8sClient.Create(ctx, policy)).To(MatchError(ContainSubstring("policy and template_ref are mutually exclusive")))
+
+			Expect(k8sClient.Delete(ctx, template)).To(Succeed())
+		})
+
+		It("Should admit a policy read from a ConfigMap", func() {
+			configMap := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "ism-policies"},
+				Data:       map[string]string{"logs.json": `{"policy":{"default_state":"hot","states":[{"name":"hot"}]}}`},
+			}
+			Expect(k8sClient.Create(ctx, configMap)).To(Succeed())
+			source := &batchv1.PolicySource{ConfigMapKeyRef: corev1.ConfigMapKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "ism-policies"},
+				Key:                  "logs.json",
+			}}
+
+			By("sending the policy through the defaulting webhook and the CRD validation")
+			policy := &batchv1.OSIndexPolicy{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "from-configmap"},
+				Spec: batchv1.OSIndexPolicySpec{
+					PolicyID:            "from-configmap",
+					OpensearhConnection: batchv1.OpensearhConnection{URL: "https://opensearch:9200"},
+					PolicyFrom:          source,
+				},
+			}
+			Expect(k8sClient.Create(ctx, policy)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, policy)).To(Succeed())
+
+			By("denying a policy setting both")
+			policy = &batchv1.OSIndexPolicy{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "both"},
+				Spec: batchv1.OSIndexPolicySpec{
+					PolicyID:            "both",
+					OpensearhConnection: batchv1.OpensearhConnection{URL: "https://opensearch:9200"},
+					PolicyFrom:          source,
+					Policy:              batchv1.OpensearchIndexPolicy{DefaultState: "hot"},
+				},
+			}
+			Expect(k8sClient.Create(ctx, policy)).To(MatchError(ContainSubstring("policy and policy_from are mutually exclusive")))
+
+			Expect(k8sClient.Delete(ctx, configMap)).To(Succeed())
+		})
+	})
 })
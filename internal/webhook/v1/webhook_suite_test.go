@@ -109,7 +109,10 @@ var _ = BeforeSuite(func() {
 	})
 	Expect(err).NotTo(HaveOccurred())
 
-	err = SetupOSIndexPolicyWebhookWithManager(mgr)
+	err = SetupOSIndexPolicyWebhookWithManager(mgr, false)
+	Expect(err).NotTo(HaveOccurred())
+
+	err = SetupClusterOSIndexPolicyWebhookWithManager(mgr, "default")
 	Expect(err).NotTo(HaveOccurred())
 
 	// +kubebuilder:scaffold:webhook